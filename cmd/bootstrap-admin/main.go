@@ -0,0 +1,49 @@
+// Команда назначает первого администратора по email уже зарегистрированного пользователя.
+// Срабатывает только один раз: если администратор уже есть, завершается с ошибкой.
+//
+//	go run ./cmd/bootstrap-admin -email admin@example.com
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"auth-system/internal/application/services"
+	"auth-system/internal/config"
+	"auth-system/internal/infrastructure/repositories"
+	"auth-system/internal/infrastructure/repositories/postgres"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the registered user to promote")
+	flag.Parse()
+
+	if *email == "" {
+		log.Fatal("Usage: bootstrap-admin -email <email>")
+	}
+
+	cfg := config.Load()
+	db := postgres.ConnectDB(cfg.DatabaseURL)
+	if err := postgres.ApplySchemaUpdates(db); err != nil {
+		log.Fatalf("Failed to update database schema: %v", err)
+	}
+
+	repos := repositories.NewRepositories(db)
+	adminService := services.NewAdminService(
+		repos.Admin,
+		repos.Event,
+		repos.User,
+		repos.Comment,
+		repos.Notification,
+		repos.RefreshToken,
+		repos.LoginAttempt,
+		services.NewAccessService(repos.User, cfg.UserStatusCacheTTL),
+	)
+
+	if err := adminService.BootstrapAdmin(context.Background(), *email); err != nil {
+		log.Fatalf("Failed to bootstrap admin: %v", err)
+	}
+
+	log.Printf("User %s is now an administrator", *email)
+}
@@ -1,17 +1,24 @@
 package main
 
 import (
+	"context"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"auth-system/internal/application/interfaces"
 	"auth-system/internal/application/interfaces/api"
 	"auth-system/internal/application/interfaces/controllers"
 	"auth-system/internal/application/services"
 	"auth-system/internal/config"
 	"auth-system/internal/infrastructure/http"
+	"auth-system/internal/infrastructure/mail"
+	"auth-system/internal/infrastructure/oauth"
 	"auth-system/internal/infrastructure/repositories"
 	"auth-system/internal/infrastructure/repositories/postgres"
+	"auth-system/internal/infrastructure/storage"
 	"auth-system/internal/pkg/utils"
 )
 
@@ -20,7 +27,7 @@ func main() {
 	cfg := config.Load()
 
 	// загрузка утилит
-	jwtUtil := utils.NewJWTUtil(cfg.JWTSecret)
+	jwtUtil := utils.NewJWTUtil(cfg.JWTSecret, cfg.AccessTokenTTL)
 	passwordUtil := utils.NewPasswordUtil()
 
 	// подключение к бд
@@ -31,23 +38,55 @@ func main() {
 	if err := postgres.SafeMigratee(db); err != nil {
 		log.Printf("Warning: %v", err)
 	}
+	if err := postgres.ApplySchemaUpdates(db); err != nil {
+		log.Fatalf("Failed to update database schema: %v", err)
+	}
 
 	log.Println("Database ready")
 
 	//загрузка репозиториев
 	repos := repositories.NewRepositories(db)
 
+	// хранилище медиафайлов
+	mediaStorage, err := setupStorage(cfg)
+	if err != nil {
+		log.Fatalf("Failed to init media storage: %v", err)
+	}
+	originalsStorage, err := setupOriginalsStorage(cfg)
+	if err != nil {
+		log.Fatalf("Failed to init originals storage: %v", err)
+	}
+
+	// фоновая обработка фотографий
+	mediaProcessor := services.NewMediaProcessor(repos.Event, mediaStorage, originalsStorage, cfg.MediaWorkers)
+	mediaProcessor.Start(context.Background())
+
+	// напоминания участникам о предстоящих мероприятиях
+	reminders := services.NewReminderScheduler(repos.Reminder, cfg.ReminderOffsets, cfg.ReminderInterval)
+	reminders.Start(context.Background())
+
+	// отправка писем
+	mailer, err := setupMailer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to init mailer: %v", err)
+	}
+
 	// загрузка сервисов
-	svc := setupServices(repos, jwtUtil, passwordUtil)
+	svc := setupServices(cfg, repos, mediaStorage, originalsStorage, mediaProcessor, mailer, jwtUtil, passwordUtil)
 
 	// загрузка контролеров
-	ctrls := setupControllers(svc)
+	ctrls := setupControllers(cfg, svc)
 
 	// запуск сервера
 	server := http.NewServer(cfg)
 
+	// локальные загрузки раздаются самим сервером
+	if cfg.StorageDriver == "local" {
+		server.GetEngine().Static("/uploads", cfg.UploadDir)
+	}
+
 	// загрузка маршрутов
-	router := setupRouter(server.GetEngine(), ctrls, jwtUtil)
+	router := setupRouter(server.GetEngine(), ctrls, jwtUtil, svc.Access)
 
 	// старт сервера
 	log.Printf("Server starting on %s", cfg.ServerPort)
@@ -66,26 +105,138 @@ func main() {
 // 	}
 // }
 
-func setupServices(repos *repositories.Repositories, jwtUtil utils.JWTUtil, passwordUtil utils.PasswordUtil) *services.Services {
+func setupStorage(cfg *config.Config) (interfaces.MediaStorage, error) {
+	if cfg.StorageDriver == "s3" {
+		return storage.NewMinioStorage(
+			context.Background(),
+			cfg.S3Endpoint,
+			cfg.S3AccessKey,
+			cfg.S3SecretKey,
+			cfg.S3Bucket,
+			cfg.S3PublicURL,
+			cfg.S3UseSSL,
+		)
+	}
+	return storage.NewLocalStorage(cfg.UploadDir, cfg.UploadURL)
+}
+
+// setupOriginalsStorage закрытое хранилище исходников фотографий: отдельный бакет
+// без публичного доступа или каталог, который сервер не раздает
+func setupOriginalsStorage(cfg *config.Config) (interfaces.MediaStorage, error) {
+	if cfg.StorageDriver == "s3" {
+		return storage.NewMinioStorage(
+			context.Background(),
+			cfg.S3Endpoint,
+			cfg.S3AccessKey,
+			cfg.S3SecretKey,
+			cfg.S3PrivateBucket,
+			"",
+			cfg.S3UseSSL,
+		)
+	}
+	return storage.NewLocalStorage(cfg.PrivateDir, "")
+}
+
+func setupMailer(cfg *config.Config) (interfaces.Mailer, error) {
+	switch cfg.MailDriver {
+	case "smtp":
+		return mail.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	case "memory":
+		return mail.NewMemoryMailer(), nil
+	default:
+		return mail.NewFileMailer(cfg.MailDir, cfg.MailFrom)
+	}
+}
+
+// setupOAuthProviders подключает провайдеров, для которых задан client id.
+// Ошибка discovery не мешает запуску: провайдер просто не будет доступен.
+func setupOAuthProviders(cfg *config.Config) []interfaces.OAuthProvider {
+	redirect := func(name string) string {
+		return strings.TrimRight(cfg.OAuthRedirectURL, "/") + "/" + name
+	}
+
+	var providers []interfaces.OAuthProvider
+	if cfg.YandexClientID != "" {
+		providers = append(providers, oauth.NewYandexProvider(cfg.YandexClientID, cfg.YandexClientSecret, redirect("yandex")))
+	}
+	if cfg.VKClientID != "" {
+		providers = append(providers, oauth.NewVKProvider(cfg.VKClientID, cfg.VKClientSecret, redirect("vk")))
+	}
+
+	oidcProviders := []struct{ name, issuer, clientID, clientSecret string }{
+		{"google", "https://accounts.google.com", cfg.GoogleClientID, cfg.GoogleClientSecret},
+		{cfg.OIDCProviderName, cfg.OIDCIssuer, cfg.OIDCClientID, cfg.OIDCClientSecret},
+	}
+	for _, p := range oidcProviders {
+		if p.clientID == "" || p.issuer == "" {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		provider, err := oauth.NewOIDCProvider(ctx, p.name, p.issuer, p.clientID, p.clientSecret, redirect(p.name))
+		cancel()
+		if err != nil {
+			log.Printf("OAuth provider %s is disabled: %v", p.name, err)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+
+	return providers
+}
+
+func setupServices(cfg *config.Config, repos *repositories.Repositories, mediaStorage, originalsStorage interfaces.MediaStorage, mediaProcessor *services.MediaProcessor, mailer interfaces.Mailer, jwtUtil utils.JWTUtil, passwordUtil utils.PasswordUtil) *services.Services {
+	access := services.NewAccessService(repos.User, cfg.UserStatusCacheTTL)
+	loginGuard := services.NewLoginGuard(repos.LoginAttempt, repos.User, repos.Notification, services.LoginGuardSettings{
+		BackoffThreshold:   cfg.LoginBackoffThreshold,
+		BackoffBase:        cfg.LoginBackoffBase,
+		BackoffMax:         cfg.LoginBackoffMax,
+		LockThreshold:      cfg.LoginLockThreshold,
+		LockDuration:       cfg.LoginLockDuration,
+		IPBackoffThreshold: cfg.LoginIPBackoffThreshold,
+		FailureWindow:      cfg.LoginFailureWindow,
+	})
+	twoFactor := services.NewTwoFactorService(repos.User, repos.RecoveryCode, passwordUtil, access, loginGuard, cfg.TOTPIssuer)
+	authSettings := services.AuthSettings{
+		RefreshTTL:            cfg.RefreshTokenTTL,
+		EmailVerificationTTL:  cfg.EmailVerificationTTL,
+		PasswordResetTokenTTL: cfg.PasswordResetTokenTTL,
+		LoginChallengeTTL:     cfg.LoginChallengeTTL,
+		AppURL:                cfg.AppURL,
+	}
+
+	event := services.NewEventService(repos.Event, repos.User, repos.Notification)
+	auth := services.NewAuthService(repos.User, repos.RefreshToken, repos.Verification, jwtUtil, passwordUtil, mailer, access, loginGuard, twoFactor, authSettings)
+
 	return &services.Services{
-		Auth:         services.NewAuthService(repos.User, jwtUtil, passwordUtil),
-		Event:        services.NewEventService(repos.Event, repos.User, repos.Notification),
+		Auth:         auth,
+		TwoFactor:    twoFactor,
+		OAuth:        services.NewOAuthService(setupOAuthProviders(cfg), repos.Identity, repos.User, auth),
+		Profile:      services.NewProfileService(repos.User, repos.Comment, mediaStorage, auth, event, cfg.MaxAvatarSizeMB<<20),
+		Event:        event,
 		Comment:      services.NewCommentService(repos.Comment, repos.User, repos.Event, repos.Notification),
+		Media:        services.NewMediaService(repos.Event, mediaStorage, originalsStorage, mediaProcessor, cfg.MaxUploadSizeMB<<20),
 		Notification: services.NewNotificationService(repos.Notification),
-		Admin:        services.NewAdminService(repos.Admin, repos.Event, repos.User, repos.Comment, repos.Notification),
+		Admin:        services.NewAdminService(repos.Admin, repos.Event, repos.User, repos.Comment, repos.Notification, repos.RefreshToken, repos.LoginAttempt, access),
+		Organizer:    services.NewOrganizerService(repos.Organizer, repos.User, repos.Admin, repos.Notification, access),
+		Access:       access,
 	}
 }
 
-func setupControllers(services *services.Services) *controllers.Controllers {
+func setupControllers(cfg *config.Config, services *services.Services) *controllers.Controllers {
 	return &controllers.Controllers{
 		Auth:         controllers.NewAuthController(services.Auth),
+		TwoFactor:    controllers.NewTwoFactorController(services.TwoFactor),
+		OAuth:        controllers.NewOAuthController(services.OAuth),
+		Profile:      controllers.NewProfileController(services.Profile),
 		Event:        controllers.NewEventController(services.Event),
 		Comment:      controllers.NewCommentController(services.Comment),
+		Media:        controllers.NewMediaController(services.Media, cfg.MaxBodySizeMB<<20),
 		Notification: controllers.NewNotificationController(services.Notification),
 		Admin:        controllers.NewAdminController(services.Admin),
+		Organizer:    controllers.NewOrganizerController(services.Organizer),
 	}
 }
 
-func setupRouter(engine *gin.Engine, ctrls *controllers.Controllers, jwtUtil utils.JWTUtil) *gin.Engine {
-	return api.SetupRoutes(engine, ctrls, jwtUtil)
+func setupRouter(engine *gin.Engine, ctrls *controllers.Controllers, jwtUtil utils.JWTUtil, accessChecker interfaces.AccessChecker) *gin.Engine {
+	return api.SetupRoutes(engine, ctrls, jwtUtil, accessChecker)
 }
@@ -22,6 +22,11 @@ type RejectEventRequest struct {
 	Reason string `json:"reason"`
 }
 
+type ChangeRoleRequest struct {
+	Role   string `json:"role" binding:"required,oneof=user organizer moderator admin superadmin"`
+	Reason string `json:"reason"`
+}
+
 type AdminActionResponse struct {
 	ID          uint   `json:"id"`
 	AdminID     uint   `json:"admin_id"`
@@ -31,3 +36,33 @@ type AdminActionResponse struct {
 	Reason      string `json:"reason"`
 	PerformedAt string `json:"performed_at"`
 }
+
+type LoginAttemptQuery struct {
+	Email      string `form:"email"`
+	IP         string `form:"ip"`
+	UserID     uint   `form:"user_id"`
+	FailedOnly bool   `form:"failed_only"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+type SuspiciousLoginQuery struct {
+	Hours       int `form:"hours" binding:"omitempty,min=1,max=720"`
+	MinFailures int `form:"min_failures" binding:"omitempty,min=1"`
+}
+
+type LoginAttemptResponse struct {
+	ID        uint   `json:"id"`
+	UserID    *uint  `json:"user_id"`
+	Email     string `json:"email"`
+	IP        string `json:"ip"`
+	Success   bool   `json:"success"`
+	Reason    string `json:"reason"`
+	CreatedAt string `json:"created_at"`
+}
+
+type LoginActivityResponse struct {
+	IP          string `json:"ip"`
+	Failures    int64  `json:"failures"`
+	Accounts    int64  `json:"accounts"`
+	LastAttempt string `json:"last_attempt"`
+}
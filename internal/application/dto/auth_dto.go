@@ -4,7 +4,6 @@ type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"required,oneof=user admin"`
 }
 
 type LoginRequest struct {
@@ -12,26 +11,112 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// AuthResponse при включенной 2FA вход идет в два шага: сначала приходит
+// только ChallengeToken, токены выдаются после проверки кода
 type AuthResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
+	Token        string        `json:"token,omitempty"`
+	RefreshToken string        `json:"refresh_token,omitempty"`
+	ExpiresAt    string        `json:"expires_at,omitempty"`
+	User         *UserResponse `json:"user,omitempty"`
+
+	TwoFactorRequired      bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken         string `json:"challenge_token,omitempty"`
+	TwoFactorSetupRequired bool   `json:"two_factor_setup_required,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type UserResponse struct {
-	ID         uint   `json:"id"`
-	Username   string `json:"username"`
-	Email      string `json:"email"`
-	Role       string `json:"role"`
-	AvatarURL  string `json:"avatar_url"`
-	IsBlocked  bool   `json:"is_blocked"`
-	LastOnline string `json:"last_online"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	ID                uint   `json:"id"`
+	Username          string `json:"username"`
+	Email             string `json:"email"`
+	Role              string `json:"role"`
+	AvatarURL         string `json:"avatar_url"`
+	Bio               string `json:"bio"`
+	IsBlocked         bool   `json:"is_blocked"`
+	HideParticipation bool   `json:"hide_participation"`
+	EmailVerified     bool   `json:"email_verified"`
+	LockedUntil       string `json:"locked_until,omitempty"`
+	TOTPEnabled       bool   `json:"totp_enabled"`
+	LastOnline        string `json:"last_online"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
 }
 
 type UserShort struct {
-	ID       uint   `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
+	ID                  uint   `json:"id"`
+	Username            string `json:"username"`
+	Email               string `json:"email,omitempty"`
+	Role                string `json:"role"`
+	AvatarURL           string `json:"avatar_url"`
+	Bio                 string `json:"bio,omitempty"`
+	IsVerifiedOrganizer bool   `json:"is_verified_organizer"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// Шестизначный код из приложения или код восстановления
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+type OAuthStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type OAuthCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+	// VK ID возвращает device_id вместе с кодом
+	DeviceID string `json:"device_id"`
+}
+
+type IdentityResponse struct {
+	Provider    string `json:"provider"`
+	Email       string `json:"email"`
+	CreatedAt   string `json:"created_at"`
+	LastLoginAt string `json:"last_login_at,omitempty"`
 }
@@ -13,18 +13,70 @@ type VoteRequest struct {
 	VoteType string `json:"vote_type" binding:"required,oneof=upvote downvote"`
 }
 
+type CommentTreeQuery struct {
+	MaxDepth int    `form:"depth" binding:"omitempty,min=1,max=50"`
+	Sort     string `form:"sort" binding:"omitempty,oneof=best top new controversial"`
+}
+
+type UserCommentsQuery struct {
+	EventID uint `form:"event_id"`
+	// IncludeReplies добавляет в выдачу ответы других пользователей на мои комментарии
+	IncludeReplies bool `form:"include_replies"`
+	Page           int  `form:"page" binding:"omitempty,min=1"`
+	Limit          int  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type UserCommentResponse struct {
+	ID         uint      `json:"id"`
+	Content    string    `json:"content"`
+	ParentID   *uint     `json:"parent_id"`
+	EventID    uint      `json:"event_id"`
+	EventTitle string    `json:"event_title"`
+	EventURL   string    `json:"event_url"`
+	Score      int       `json:"score"`
+	Upvotes    int       `json:"upvotes"`
+	Downvotes  int       `json:"downvotes"`
+	ReplyCount int       `json:"reply_count"`
+	CreatedAt  string    `json:"created_at"`
+	UpdatedAt  string    `json:"updated_at"`
+	User       UserShort `json:"user"`
+	// IsReply отмечает чужой ответ на комментарий пользователя
+	IsReply bool `json:"is_reply"`
+}
+
+type UserCommentsResponse struct {
+	Comments []UserCommentResponse `json:"comments"`
+	Total    int64                 `json:"total"`
+	Page     int                   `json:"page"`
+	Limit    int                   `json:"limit"`
+}
+
 type CommentResponse struct {
-	ID        uint              `json:"id"`
-	Content   string            `json:"content"`
-	EventID   uint              `json:"event_id"`
-	UserID    uint              `json:"user_id"`
-	ParentID  *uint             `json:"parent_id"`
-	Score     int               `json:"score"`
-	IsDeleted bool              `json:"is_deleted"`
-	CreatedAt string            `json:"created_at"`
-	UpdatedAt string            `json:"updated_at"`
-	User      UserShort         `json:"user"`
-	Replies   []CommentResponse `json:"replies"`
+	ID         uint      `json:"id"`
+	Content    string    `json:"content"`
+	EventID    uint      `json:"event_id"`
+	UserID     uint      `json:"user_id"`
+	ParentID   *uint     `json:"parent_id"`
+	Score      int       `json:"score"`
+	Upvotes    int       `json:"upvotes"`
+	Downvotes  int       `json:"downvotes"`
+	IsDeleted  bool      `json:"is_deleted"`
+	CreatedAt  string    `json:"created_at"`
+	UpdatedAt  string    `json:"updated_at"`
+	User       UserShort `json:"user"`
+	Depth      int       `json:"depth"`
+	ReplyCount int       `json:"reply_count"`
+	// HasMoreReplies отмечает ветку, обрезанную по глубине ("продолжить ветку")
+	HasMoreReplies bool              `json:"has_more_replies"`
+	Replies        []CommentResponse `json:"replies"`
+}
+
+// HidePrivateData убирает данные, недоступные гостям, во всей ветке
+func (c *CommentResponse) HidePrivateData() {
+	c.User.Email = ""
+	for i := range c.Replies {
+		c.Replies[i].HidePrivateData()
+	}
 }
 
 type CommentVoteResponse struct {
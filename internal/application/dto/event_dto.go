@@ -1,6 +1,12 @@
 package dto
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type CreateEventRequest struct {
 	Title           string    `json:"title" binding:"required"`
@@ -25,31 +31,65 @@ type UpdateEventRequest struct {
 }
 
 type EventFilter struct {
-	Type []string  `json:"type"`
-	Date time.Time `json:"date"`
-	Tags string    `json:"tags"`
+	Type     []string     `json:"type"`
+	Date     time.Time    `json:"date"`
+	Tags     string       `json:"tags"`
+	BBox     *BoundingBox `json:"bbox" form:"bbox"`
+	Lat      *float64     `json:"lat" form:"lat" binding:"omitempty,min=-90,max=90"`
+	Lon      *float64     `json:"lon" form:"lon" binding:"omitempty,min=-180,max=180"`
+	RadiusKm float64      `json:"radius_km" form:"radius_km" binding:"omitempty,gt=0,max=500"`
+	SortBy   string       `json:"sort_by" form:"sort_by" binding:"omitempty,oneof=created date distance"`
+}
+
+// Validate проверяет согласованность гео-параметров фильтра
+func (f *EventFilter) Validate() error {
+	if f.BBox != nil {
+		if err := f.BBox.Validate(); err != nil {
+			return err
+		}
+	}
+	if (f.Lat == nil) != (f.Lon == nil) {
+		return errors.New("lat and lon must be provided together")
+	}
+	if f.Lat == nil && f.RadiusKm > 0 {
+		return errors.New("radius_km requires lat and lon")
+	}
+	if f.Lat == nil && f.SortBy == "distance" {
+		return errors.New("sort_by=distance requires lat and lon")
+	}
+	return nil
 }
 
 type EventResponse struct {
-	ID                uint      `json:"id"`
-	Title             string    `json:"title"`
-	Description       string    `json:"description"`
-	EventDate         time.Time `json:"event_date"`
-	Latitude          float64   `json:"latitude"`
-	Longitude         float64   `json:"longitude"`
-	Type              string    `json:"type"`
-	MaxParticipants   *int      `json:"max_participants"`
-	Price             float64   `json:"price"`
-	Address           string    `json:"address"`
-	IsVerified        bool      `json:"is_verified"`
-	IsActive          bool      `json:"is_active"`
-	CreatorID         uint      `json:"creator_id"`
-	Creator           UserShort `json:"creator"`
-	ParticipantsCount int       `json:"participants_count"`
-	CreatedAt         string    `json:"created_at"`
-	UpdatedAt         string    `json:"updated_at"`
-	Tags              []Tag     `json:"tags"`
-	Media             []Media   `json:"media"`
+	ID                uint           `json:"id"`
+	Title             string         `json:"title"`
+	Description       string         `json:"description"`
+	EventDate         time.Time      `json:"event_date"`
+	Latitude          float64        `json:"latitude"`
+	Longitude         float64        `json:"longitude"`
+	Type              string         `json:"type"`
+	MaxParticipants   *int           `json:"max_participants"`
+	Price             float64        `json:"price"`
+	Address           string         `json:"address"`
+	IsVerified        bool           `json:"is_verified"`
+	IsActive          bool           `json:"is_active"`
+	IsCancelled       bool           `json:"is_cancelled"`
+	CancelledAt       *time.Time     `json:"cancelled_at,omitempty"`
+	CancelReason      string         `json:"cancellation_reason,omitempty"`
+	CreatorID         uint           `json:"creator_id"`
+	Creator           UserShort      `json:"creator"`
+	ParticipantsCount int            `json:"participants_count"`
+	RSVPCounts        map[string]int `json:"rsvp_counts"`
+	DistanceKm        *float64       `json:"distance_km,omitempty"`
+	CreatedAt         string         `json:"created_at"`
+	UpdatedAt         string         `json:"updated_at"`
+	Tags              []Tag          `json:"tags"`
+	Media             []Media        `json:"media"`
+}
+
+// HidePrivateData убирает данные, недоступные гостям
+func (e *EventResponse) HidePrivateData() {
+	e.Creator.Email = ""
 }
 
 type EventFullResponse struct {
@@ -65,19 +105,86 @@ type Tag struct {
 }
 
 type Media struct {
-	ID         uint   `json:"id"`
-	FileURL    string `json:"file_url"`
-	FileType   string `json:"file_type"`
-	OrderIndex int    `json:"order_index"`
+	ID         uint        `json:"id"`
+	FileURL    string      `json:"file_url"`
+	FileType   string      `json:"file_type"`
+	Status     string      `json:"status"`
+	Sizes      *MediaSizes `json:"sizes,omitempty"`
+	OrderIndex int         `json:"order_index"`
+}
+
+// MediaSizes ссылки на обработанные копии фотографии
+type MediaSizes struct {
+	Thumbnail string `json:"thumbnail"`
+	Card      string `json:"card"`
+	Full      string `json:"full"`
+}
+
+type ReorderMediaRequest struct {
+	MediaIDs []uint `json:"media_ids" binding:"required"`
+}
+
+type CancelEventRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// RemindersRequest включает или отключает напоминания участнику о конкретном мероприятии
+type RemindersRequest struct {
+	Muted *bool `json:"muted" binding:"required"`
+}
+
+type ParticipateQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=going interested maybe declined"`
+	// Waitlist=false отказывает в записи при отсутствии мест вместо постановки в очередь
+	Waitlist *bool `form:"waitlist"`
+}
+
+type ParticipatedEventsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=going waitlisted interested maybe declined"`
+}
+
+type ParticipationResponse struct {
+	EventID        uint   `json:"event_id"`
+	Status         string `json:"status"`
+	RemindersMuted bool   `json:"reminders_muted"`
+	// WaitlistPosition место в очереди, начиная с 1; только для статуса waitlisted
+	WaitlistPosition int64 `json:"waitlist_position,omitempty"`
+}
+
+type ParticipantsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=going waitlisted interested maybe declined"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+type ParticipantsExportQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=going waitlisted interested maybe declined"`
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
 }
 
 type EventParticipantResponse struct {
-	EventID  uint       `json:"event_id"`
-	UserID   uint       `json:"user_id"`
-	Status   string     `json:"status"`
-	JoinedAt string     `json:"joined_at"`
-	User     UserShort  `json:"user"`
-	Event    EventShort `json:"event"`
+	EventID  uint        `json:"event_id"`
+	UserID   uint        `json:"user_id"`
+	Status   string      `json:"status"`
+	JoinedAt string      `json:"joined_at"`
+	User     UserShort   `json:"user"`
+	Event    *EventShort `json:"event,omitempty"`
+	// Hidden участник скрыл участие в настройках приватности
+	Hidden bool `json:"hidden"`
+}
+
+type EventParticipantsResponse struct {
+	Participants []EventParticipantResponse `json:"participants"`
+	Total        int64                      `json:"total"`
+	Page         int                        `json:"page"`
+	Limit        int                        `json:"limit"`
+}
+
+// ExportFile готовый к отдаче файл выгрузки
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
 }
 
 type EventShort struct {
@@ -86,3 +193,60 @@ type EventShort struct {
 	Type     string `json:"type"`
 	IsActive bool   `json:"is_active"`
 }
+
+// BoundingBox область карты в формате "minLon,minLat,maxLon,maxLat"
+type BoundingBox struct {
+	MinLon float64 `json:"min_lon"`
+	MinLat float64 `json:"min_lat"`
+	MaxLon float64 `json:"max_lon"`
+	MaxLat float64 `json:"max_lat"`
+}
+
+// UnmarshalParam позволяет gin разбирать bbox из query-параметра
+func (b *BoundingBox) UnmarshalParam(param string) error {
+	parts := strings.Split(param, ",")
+	if len(parts) != 4 {
+		return errors.New("bbox must be minLon,minLat,maxLon,maxLat")
+	}
+
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return fmt.Errorf("invalid bbox value %q", part)
+		}
+		values[i] = value
+	}
+
+	b.MinLon, b.MinLat, b.MaxLon, b.MaxLat = values[0], values[1], values[2], values[3]
+	return b.Validate()
+}
+
+func (b *BoundingBox) Validate() error {
+	if b.MinLon < -180 || b.MaxLon > 180 || b.MinLat < -90 || b.MaxLat > 90 {
+		return errors.New("bbox is out of range")
+	}
+	if b.MinLon >= b.MaxLon || b.MinLat >= b.MaxLat {
+		return errors.New("bbox min values must be less than max values")
+	}
+	return nil
+}
+
+type ClusterRequest struct {
+	BBox *BoundingBox `form:"bbox" binding:"required"`
+	Zoom int          `form:"zoom" binding:"min=0,max=22"`
+	Type []string     `form:"type"`
+}
+
+type ClusterResponse struct {
+	Zoom     int             `json:"zoom"`
+	Clusters []EventCluster  `json:"clusters"`
+	Events   []EventResponse `json:"events"`
+}
+
+type EventCluster struct {
+	Count     int         `json:"count"`
+	Latitude  float64     `json:"latitude"`
+	Longitude float64     `json:"longitude"`
+	Bounds    BoundingBox `json:"bounds"`
+}
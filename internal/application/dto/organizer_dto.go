@@ -0,0 +1,27 @@
+package dto
+
+type OrganizerApplicationRequest struct {
+	Message string `json:"message" binding:"required,min=10,max=2000"`
+}
+
+type OrganizerApplicationQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending approved rejected"`
+}
+
+type ReviewApplicationRequest struct {
+	Note string `json:"note"`
+}
+
+type RevokeOrganizerRequest struct {
+	Reason string `json:"reason"`
+}
+
+type OrganizerApplicationResponse struct {
+	ID         uint      `json:"id"`
+	User       UserShort `json:"user"`
+	Message    string    `json:"message"`
+	Status     string    `json:"status"`
+	ReviewNote string    `json:"review_note,omitempty"`
+	CreatedAt  string    `json:"created_at"`
+	ReviewedAt string    `json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+// UpdateProfileRequest принимается как JSON или multipart-форма (с файлом avatar);
+// незаданные поля не меняются
+type UpdateProfileRequest struct {
+	Username *string `json:"username" form:"username" binding:"omitempty,min=3,max=50"`
+	Email    *string `json:"email" form:"email" binding:"omitempty,email"`
+	Bio      *string `json:"bio" form:"bio" binding:"omitempty,max=500"`
+	// Скрыть список посещенных мероприятий в публичном профиле
+	HideParticipation *bool `json:"hide_participation" form:"hide_participation"`
+}
+
+type UpdateProfileResponse struct {
+	User *UserResponse `json:"user"`
+	// Новый адрес, на который отправлено письмо для подтверждения
+	PendingEmail string `json:"pending_email,omitempty"`
+}
+
+// PublicProfileResponse профиль, доступный любому посетителю; почта не раскрывается
+type PublicProfileResponse struct {
+	ID                  uint   `json:"id"`
+	Username            string `json:"username"`
+	Role                string `json:"role"`
+	AvatarURL           string `json:"avatar_url"`
+	Bio                 string `json:"bio"`
+	IsVerifiedOrganizer bool   `json:"is_verified_organizer"`
+	CreatedAt           string `json:"created_at"`
+	CommentsCount       int64  `json:"comments_count"`
+	// Karma сумма оценок всех комментариев пользователя
+	Karma         int64           `json:"karma"`
+	CreatedEvents []EventResponse `json:"created_events"`
+	// AttendedEvents не заполняется, если пользователь скрыл участие
+	AttendedEvents      []EventResponse `json:"attended_events"`
+	ParticipationHidden bool            `json:"participation_hidden"`
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"auth-system/internal/application/interfaces"
 	"auth-system/internal/application/interfaces/controllers"
+	"auth-system/internal/domain/entities"
 	"auth-system/internal/infrastructure/http/middlewares"
 	"auth-system/internal/pkg/utils"
 
@@ -13,6 +15,7 @@ func SetupRoutes(
 	router *gin.Engine,
 	ctrls *controllers.Controllers,
 	jwtUtil utils.JWTUtil,
+	accessChecker interfaces.AccessChecker,
 ) *gin.Engine {
 	// CORS configuration
 	config := cors.DefaultConfig()
@@ -28,30 +31,77 @@ func SetupRoutes(
 		// Auth routes
 		api.POST("/register", ctrls.Auth.Register)
 		api.POST("/login", ctrls.Auth.Login)
+		api.POST("/login/2fa", ctrls.Auth.LoginTwoFactor)
+
+		// Social login (OIDC/OAuth2)
+		api.GET("/oauth/providers", ctrls.OAuth.GetProviders)
+		api.GET("/oauth/:provider/authorize", ctrls.OAuth.StartLogin)
+		api.POST("/oauth/:provider/callback", ctrls.OAuth.Callback)
+		api.POST("/token/refresh", ctrls.Auth.RefreshToken)
+		api.POST("/logout", ctrls.Auth.Logout)
+		api.POST("/email/verify", ctrls.Auth.VerifyEmail)
+		api.POST("/email/change/confirm", ctrls.Auth.ConfirmEmailChange)
+		api.POST("/password/forgot", ctrls.Auth.ForgotPassword)
+		api.POST("/password/reset", ctrls.Auth.ResetPassword)
+	}
+
+	// Public read-only routes (guests and logged-in users)
+	public := api.Group("")
+	public.Use(middlewares.OptionalAuthMiddleware(jwtUtil, accessChecker))
+	{
+		public.GET("/events", ctrls.Event.GetEvents)
+		public.POST("/events/filter", ctrls.Event.FilterEvents)
+		public.GET("/events/clusters", ctrls.Event.GetClusters)
+		public.GET("/events/:id", ctrls.Event.GetEventByID)
+		public.GET("/events/:id/comments", ctrls.Comment.GetComments)
+		public.GET("/comments/:commentId/thread", ctrls.Comment.GetThread)
+		public.GET("/users/:id", ctrls.Profile.GetPublicProfile)
 	}
 
 	// Protected routes
 	protected := api.Group("")
-	protected.Use(middlewares.AuthMiddleware(jwtUtil))
+	protected.Use(middlewares.AuthMiddleware(jwtUtil, accessChecker))
 	{
 		protected.GET("/profile", ctrls.Auth.GetProfile)
+		protected.PUT("/profile", ctrls.Profile.UpdateProfile)
+		protected.DELETE("/profile/avatar", ctrls.Profile.DeleteAvatar)
+		protected.POST("/email/verify/resend", ctrls.Auth.ResendVerification)
+		protected.PUT("/password", ctrls.Auth.ChangePassword)
+
+		// Two-factor authentication
+		protected.GET("/2fa", ctrls.TwoFactor.GetStatus)
+		protected.POST("/2fa/setup", ctrls.TwoFactor.Setup)
+		protected.POST("/2fa/enable", ctrls.TwoFactor.Enable)
+		protected.POST("/2fa/disable", ctrls.TwoFactor.Disable)
+		protected.POST("/2fa/recovery-codes", ctrls.TwoFactor.RegenerateRecoveryCodes)
+
+		// Linked social accounts
+		protected.GET("/identities", ctrls.OAuth.GetIdentities)
+		protected.POST("/identities/:provider", ctrls.OAuth.StartLink)
+		protected.DELETE("/identities/:provider", ctrls.OAuth.Unlink)
 
 		// Event routes
 		eventRoutes := protected.Group("/events")
 		{
-			eventRoutes.GET("", ctrls.Event.GetEvents)
 			eventRoutes.POST("", ctrls.Event.CreateEvent)
-			eventRoutes.POST("/filter", ctrls.Event.FilterEvents)
 
 			// Event-specific routes
-			eventRoutes.GET("/:id", ctrls.Event.GetEventByID)
 			eventRoutes.PUT("/:id", ctrls.Event.UpdateEvent)
 			eventRoutes.DELETE("/:id", ctrls.Event.DeleteEvent)
+			eventRoutes.POST("/:id/cancel", ctrls.Event.CancelEvent)
 			eventRoutes.POST("/:id/participate", ctrls.Event.Participate)
+			eventRoutes.GET("/:id/participate", ctrls.Event.GetParticipation)
+			eventRoutes.GET("/:id/participants", ctrls.Event.GetEventParticipants)
+			eventRoutes.GET("/:id/participants/export", ctrls.Event.ExportParticipants)
 			eventRoutes.DELETE("/:id/participate", ctrls.Event.CancelParticipation)
+			eventRoutes.PUT("/:id/reminders", ctrls.Event.SetReminders)
+
+			// Media gallery
+			eventRoutes.POST("/:id/media", ctrls.Media.UploadMedia)
+			eventRoutes.PUT("/:id/media/order", ctrls.Media.ReorderMedia)
+			eventRoutes.DELETE("/:id/media/:mediaId", ctrls.Media.DeleteMedia)
 
 			// Comments for specific event
-			eventRoutes.GET("/:id/comments", ctrls.Comment.GetComments)
 			eventRoutes.POST("/:id/comments", ctrls.Comment.CreateComment)
 		}
 
@@ -63,6 +113,11 @@ func SetupRoutes(
 		// User-specific events
 		protected.GET("/user/events", ctrls.Event.GetUserEvents)
 		protected.GET("/user/participated", ctrls.Event.GetParticipatedEvents)
+		protected.GET("/user/comments", ctrls.Comment.GetUserComments)
+
+		// Organizer verification
+		protected.POST("/organizer/application", ctrls.Organizer.Apply)
+		protected.GET("/organizer/application", ctrls.Organizer.GetMyApplication)
 
 		// Notification routes
 		protected.GET("/notifications", ctrls.Notification.GetNotifications)
@@ -71,18 +126,27 @@ func SetupRoutes(
 
 		// Admin routes
 		adminRoutes := protected.Group("/admin")
-		adminRoutes.Use(middlewares.RoleMiddleware("admin"))
+		adminRoutes.Use(middlewares.PermissionMiddleware(entities.PermAdminPanel), middlewares.TwoFactorEnrolledMiddleware())
 		{
 			adminRoutes.GET("/dashboard", ctrls.Admin.GetAdminDashboard)
-			adminRoutes.GET("/events", ctrls.Admin.GetAllEvents)
-			adminRoutes.PUT("/events/:eventId/verify", ctrls.Admin.VerifyEvent)
-			adminRoutes.PUT("/events/:eventId/reject", ctrls.Admin.RejectEvent)
-			adminRoutes.DELETE("/events/:eventId", ctrls.Admin.DeleteEvent)
-			adminRoutes.GET("/users", ctrls.Admin.GetAllUsers)
-			adminRoutes.PUT("/users/:userId/block", ctrls.Admin.BlockUser)
-			adminRoutes.PUT("/users/:userId/unblock", ctrls.Admin.UnblockUser)
-			adminRoutes.DELETE("/comments/:commentId", ctrls.Admin.DeleteComment)
-			adminRoutes.GET("/statistics", ctrls.Admin.GetStatistics)
+			adminRoutes.GET("/events", middlewares.PermissionMiddleware(entities.PermEventsViewAll), ctrls.Admin.GetAllEvents)
+			adminRoutes.GET("/events/pending", middlewares.PermissionMiddleware(entities.PermEventsVerify), ctrls.Admin.GetPendingEvents)
+			adminRoutes.PUT("/events/:eventId/verify", middlewares.PermissionMiddleware(entities.PermEventsVerify), ctrls.Admin.VerifyEvent)
+			adminRoutes.PUT("/events/:eventId/reject", middlewares.PermissionMiddleware(entities.PermEventsVerify), ctrls.Admin.RejectEvent)
+			adminRoutes.DELETE("/events/:eventId", middlewares.PermissionMiddleware(entities.PermEventsDelete), ctrls.Admin.DeleteEvent)
+			adminRoutes.GET("/users", middlewares.PermissionMiddleware(entities.PermUsersView), ctrls.Admin.GetAllUsers)
+			adminRoutes.PUT("/users/:userId/block", middlewares.PermissionMiddleware(entities.PermUsersBlock), ctrls.Admin.BlockUser)
+			adminRoutes.PUT("/users/:userId/unblock", middlewares.PermissionMiddleware(entities.PermUsersBlock), ctrls.Admin.UnblockUser)
+			adminRoutes.PUT("/users/:userId/unlock", middlewares.PermissionMiddleware(entities.PermUsersBlock), ctrls.Admin.UnlockUser)
+			adminRoutes.GET("/login-attempts", middlewares.PermissionMiddleware(entities.PermUsersView), ctrls.Admin.GetLoginAttempts)
+			adminRoutes.GET("/login-attempts/suspicious", middlewares.PermissionMiddleware(entities.PermUsersView), ctrls.Admin.GetSuspiciousLogins)
+			adminRoutes.PUT("/users/:userId/role", middlewares.PermissionMiddleware(entities.PermUsersManageRoles), ctrls.Admin.ChangeUserRole)
+			adminRoutes.DELETE("/users/:userId/organizer", middlewares.PermissionMiddleware(entities.PermOrganizersVerify), ctrls.Organizer.RevokeVerification)
+			adminRoutes.GET("/organizer-applications", middlewares.PermissionMiddleware(entities.PermOrganizersVerify), ctrls.Organizer.GetApplications)
+			adminRoutes.PUT("/organizer-applications/:applicationId/approve", middlewares.PermissionMiddleware(entities.PermOrganizersVerify), ctrls.Organizer.ApproveApplication)
+			adminRoutes.PUT("/organizer-applications/:applicationId/reject", middlewares.PermissionMiddleware(entities.PermOrganizersVerify), ctrls.Organizer.RejectApplication)
+			adminRoutes.DELETE("/comments/:commentId", middlewares.PermissionMiddleware(entities.PermCommentsDelete), ctrls.Admin.DeleteComment)
+			adminRoutes.GET("/statistics", middlewares.PermissionMiddleware(entities.PermStatisticsView), ctrls.Admin.GetStatistics)
 		}
 	}
 
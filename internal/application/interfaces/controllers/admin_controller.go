@@ -136,6 +136,78 @@ func (c *AdminController) UnblockUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "User unblocked"})
 }
 
+func (c *AdminController) UnlockUser(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	adminID, _ := ctx.Get("user_id")
+	err = c.adminService.UnlockUser(ctx.Request.Context(), uint(userID), adminID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User unlocked"})
+}
+
+func (c *AdminController) GetLoginAttempts(ctx *gin.Context) {
+	var query dto.LoginAttemptQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attempts, err := c.adminService.GetLoginAttempts(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attempts)
+}
+
+func (c *AdminController) GetSuspiciousLogins(ctx *gin.Context) {
+	var query dto.SuspiciousLoginQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	activity, err := c.adminService.GetSuspiciousLogins(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, activity)
+}
+
+func (c *AdminController) ChangeUserRole(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req dto.ChangeRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID, _ := ctx.Get("user_id")
+	err = c.adminService.ChangeUserRole(ctx.Request.Context(), uint(userID), adminID.(uint), req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "User role changed"})
+}
+
 func (c *AdminController) DeleteComment(ctx *gin.Context) {
 	commentID, err := strconv.ParseUint(ctx.Param("commentId"), 10, 32)
 	if err != nil {
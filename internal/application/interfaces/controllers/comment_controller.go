@@ -1,11 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"auth-system/internal/application/dto"
 	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,15 +27,57 @@ func (c *CommentController) GetComments(ctx *gin.Context) {
 		return
 	}
 
-	comments, err := c.commentService.GetComments(ctx.Request.Context(), uint(eventID))
+	var query dto.CommentTreeQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comments, err := c.commentService.GetComments(ctx.Request.Context(), uint(eventID), query)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if isGuest(ctx) {
+		for i := range comments {
+			comments[i].HidePrivateData()
+		}
+	}
 
 	ctx.JSON(http.StatusOK, comments)
 }
 
+func (c *CommentController) GetThread(ctx *gin.Context) {
+	commentID, err := strconv.ParseUint(ctx.Param("commentId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var query dto.CommentTreeQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	thread, err := c.commentService.GetThread(ctx.Request.Context(), uint(commentID), query)
+	if errors.Is(err, entities.ErrCommentNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if isGuest(ctx) {
+		for i := range thread {
+			thread[i].HidePrivateData()
+		}
+	}
+
+	ctx.JSON(http.StatusOK, thread)
+}
+
 func (c *CommentController) CreateComment(ctx *gin.Context) {
 	eventID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
@@ -119,3 +163,20 @@ func (c *CommentController) VoteComment(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, comment)
 }
+
+func (c *CommentController) GetUserComments(ctx *gin.Context) {
+	var query dto.UserCommentsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	comments, err := c.commentService.GetUserComments(ctx.Request.Context(), userID.(uint), query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, comments)
+}
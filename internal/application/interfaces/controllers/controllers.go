@@ -1,10 +1,23 @@
 package controllers
 
+import "github.com/gin-gonic/gin"
+
 // Controllers объединяет все контроллеры для удобной передачи в роутер
 type Controllers struct {
 	Auth         *AuthController
+	TwoFactor    *TwoFactorController
+	OAuth        *OAuthController
+	Profile      *ProfileController
 	Event        *EventController
 	Comment      *CommentController
+	Media        *MediaController
 	Notification *NotificationController
 	Admin        *AdminController
+	Organizer    *OrganizerController
+}
+
+// isGuest сообщает, что запрос пришел без валидного токена
+func isGuest(ctx *gin.Context) bool {
+	_, exists := ctx.Get("user_id")
+	return !exists
 }
@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"auth-system/internal/application/dto"
 	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,12 +28,21 @@ func (c *EventController) GetEvents(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := filter.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	events, err := c.eventService.GetEvents(ctx.Request.Context(), filter)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if isGuest(ctx) {
+		for i := range events {
+			events[i].HidePrivateData()
+		}
+	}
 
 	ctx.JSON(http.StatusOK, events)
 }
@@ -47,6 +59,9 @@ func (c *EventController) GetEventByID(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	if isGuest(ctx) {
+		event.HidePrivateData()
+	}
 
 	ctx.JSON(http.StatusOK, event)
 }
@@ -84,7 +99,7 @@ func (c *EventController) UpdateEvent(ctx *gin.Context) {
 	userID, _ := ctx.Get("user_id")
 	event, err := c.eventService.UpdateEvent(ctx.Request.Context(), uint(id), req, userID.(uint))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondParticipationError(ctx, err)
 		return
 	}
 
@@ -108,6 +123,29 @@ func (c *EventController) DeleteEvent(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Event deleted successfully"})
 }
 
+func (c *EventController) CancelEvent(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req dto.CancelEventRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	event, err := c.eventService.CancelEvent(ctx.Request.Context(), uint(id), userID.(uint), req)
+	if err != nil {
+		respondParticipationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, event)
+}
+
 func (c *EventController) Participate(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
@@ -115,14 +153,125 @@ func (c *EventController) Participate(ctx *gin.Context) {
 		return
 	}
 
+	var query dto.ParticipateQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	participation, err := c.eventService.Participate(ctx.Request.Context(), uint(id), userID.(uint), query)
+	if err != nil {
+		respondParticipationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, participation)
+}
+
+func (c *EventController) SetReminders(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req dto.RemindersRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	userID, _ := ctx.Get("user_id")
-	err = c.eventService.Participate(ctx.Request.Context(), uint(id), userID.(uint))
+	participation, err := c.eventService.SetReminders(ctx.Request.Context(), uint(id), userID.(uint), req)
 	if err != nil {
+		respondParticipationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, participation)
+}
+
+// respondParticipationError отличает конфликты записи от внутренних ошибок
+func respondParticipationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entities.ErrEventFull),
+		errors.Is(err, entities.ErrEventCancelled),
+		errors.Is(err, entities.ErrAlreadyParticipating),
+		errors.Is(err, entities.ErrAlreadyWaitlisted):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrNotParticipating),
+		errors.Is(err, entities.ErrEventNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrNotAuthorized):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (c *EventController) GetEventParticipants(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var query dto.ParticipantsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	participants, err := c.eventService.GetEventParticipants(ctx.Request.Context(), uint(id), userID.(uint), query)
+	if err != nil {
+		respondParticipationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, participants)
+}
+
+func (c *EventController) ExportParticipants(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var query dto.ParticipantsExportQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	file, err := c.eventService.ExportParticipants(ctx.Request.Context(), uint(id), userID.(uint), query)
+	if err != nil {
+		respondParticipationError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.FileName))
+	ctx.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func (c *EventController) GetParticipation(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Successfully joined event"})
+	userID, _ := ctx.Get("user_id")
+	participation, err := c.eventService.GetParticipation(ctx.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, participation)
 }
 
 func (c *EventController) CancelParticipation(ctx *gin.Context) {
@@ -135,7 +284,7 @@ func (c *EventController) CancelParticipation(ctx *gin.Context) {
 	userID, _ := ctx.Get("user_id")
 	err = c.eventService.CancelParticipation(ctx.Request.Context(), uint(id), userID.(uint))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondParticipationError(ctx, err)
 		return
 	}
 
@@ -154,8 +303,14 @@ func (c *EventController) GetUserEvents(ctx *gin.Context) {
 }
 
 func (c *EventController) GetParticipatedEvents(ctx *gin.Context) {
+	var query dto.ParticipatedEventsQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	userID, _ := ctx.Get("user_id")
-	events, err := c.eventService.GetParticipatedEvents(ctx.Request.Context(), userID.(uint))
+	events, err := c.eventService.GetParticipatedEvents(ctx.Request.Context(), userID.(uint), query)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -170,12 +325,42 @@ func (c *EventController) FilterEvents(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := filter.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	events, err := c.eventService.FilterEvents(ctx.Request.Context(), filter)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if isGuest(ctx) {
+		for i := range events {
+			events[i].HidePrivateData()
+		}
+	}
 
 	ctx.JSON(http.StatusOK, events)
 }
+
+func (c *EventController) GetClusters(ctx *gin.Context) {
+	var req dto.ClusterRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clusters, err := c.eventService.GetClusters(ctx.Request.Context(), req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if isGuest(ctx) {
+		for i := range clusters.Events {
+			clusters.Events[i].HidePrivateData()
+		}
+	}
+
+	ctx.JSON(http.StatusOK, clusters)
+}
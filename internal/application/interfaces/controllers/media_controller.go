@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"auth-system/internal/application/dto"
+	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaController struct {
+	mediaService interfaces.MediaService
+	maxBodySize  int64
+}
+
+func NewMediaController(mediaService interfaces.MediaService, maxBodySize int64) *MediaController {
+	return &MediaController{
+		mediaService: mediaService,
+		maxBodySize:  maxBodySize,
+	}
+}
+
+func (c *MediaController) UploadMedia(ctx *gin.Context) {
+	eventID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	// Тело ограничиваем до разбора формы, иначе оно целиком попадет на диск
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.maxBodySize)
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body is too large"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	media, err := c.mediaService.UploadMedia(ctx.Request.Context(), uint(eventID), userID.(uint), form.File["files"])
+	if err != nil {
+		respondMediaError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, media)
+}
+
+func (c *MediaController) ReorderMedia(ctx *gin.Context) {
+	eventID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	var req dto.ReorderMediaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	media, err := c.mediaService.ReorderMedia(ctx.Request.Context(), uint(eventID), userID.(uint), req)
+	if err != nil {
+		respondMediaError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, media)
+}
+
+func (c *MediaController) DeleteMedia(ctx *gin.Context) {
+	eventID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	mediaID, err := strconv.ParseUint(ctx.Param("mediaId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid media ID"})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	err = c.mediaService.DeleteMedia(ctx.Request.Context(), uint(eventID), uint(mediaID), userID.(uint))
+	if err != nil {
+		respondMediaError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Media deleted successfully"})
+}
+
+// respondMediaError отличает ошибки доступа и проверки файлов от сбоев хранилища и БД
+func respondMediaError(ctx *gin.Context, err error) {
+	var validationErr *entities.MediaValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrEventNotFound), errors.Is(err, entities.ErrMediaNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, entities.ErrNotAuthorized):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
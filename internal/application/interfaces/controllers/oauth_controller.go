@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+
+	"auth-system/internal/application/dto"
+	"auth-system/internal/application/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthController struct {
+	oauthService interfaces.OAuthService
+}
+
+func NewOAuthController(oauthService interfaces.OAuthService) *OAuthController {
+	return &OAuthController{oauthService: oauthService}
+}
+
+func (c *OAuthController) GetProviders(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"providers": c.oauthService.Providers()})
+}
+
+func (c *OAuthController) StartLogin(ctx *gin.Context) {
+	response, err := c.oauthService.StartLogin(ctx.Request.Context(), ctx.Param("provider"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (c *OAuthController) StartLink(ctx *gin.Context) {
+	userID, _ := ctx.Get("user_id")
+	response, err := c.oauthService.StartLink(ctx.Request.Context(), ctx.Param("provider"), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (c *OAuthController) Callback(ctx *gin.Context) {
+	var req dto.OAuthCallbackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := c.oauthService.Callback(ctx.Request.Context(), ctx.Param("provider"), req, ctx.ClientIP())
+	if err != nil {
+		respondLoginError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (c *OAuthController) GetIdentities(ctx *gin.Context) {
+	userID, _ := ctx.Get("user_id")
+	identities, err := c.oauthService.GetIdentities(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, identities)
+}
+
+func (c *OAuthController) Unlink(ctx *gin.Context) {
+	userID, _ := ctx.Get("user_id")
+	if err := c.oauthService.Unlink(ctx.Request.Context(), userID.(uint), ctx.Param("provider")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Provider unlinked"})
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"auth-system/internal/application/dto"
+	"auth-system/internal/application/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizerController struct {
+	organizerService interfaces.OrganizerService
+}
+
+func NewOrganizerController(organizerService interfaces.OrganizerService) *OrganizerController {
+	return &OrganizerController{organizerService: organizerService}
+}
+
+func (c *OrganizerController) Apply(ctx *gin.Context) {
+	var req dto.OrganizerApplicationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	application, err := c.organizerService.Apply(ctx.Request.Context(), userID.(uint), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, application)
+}
+
+func (c *OrganizerController) GetMyApplication(ctx *gin.Context) {
+	userID, _ := ctx.Get("user_id")
+	application, err := c.organizerService.GetMyApplication(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, application)
+}
+
+func (c *OrganizerController) GetApplications(ctx *gin.Context) {
+	var query dto.OrganizerApplicationQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	applications, err := c.organizerService.GetApplications(ctx.Request.Context(), query)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, applications)
+}
+
+func (c *OrganizerController) ApproveApplication(ctx *gin.Context) {
+	applicationID, err := strconv.ParseUint(ctx.Param("applicationId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+
+	var req dto.ReviewApplicationRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	adminID, _ := ctx.Get("user_id")
+	err = c.organizerService.ApproveApplication(ctx.Request.Context(), uint(applicationID), adminID.(uint), req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Application approved"})
+}
+
+func (c *OrganizerController) RejectApplication(ctx *gin.Context) {
+	applicationID, err := strconv.ParseUint(ctx.Param("applicationId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+
+	var req dto.ReviewApplicationRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	adminID, _ := ctx.Get("user_id")
+	err = c.organizerService.RejectApplication(ctx.Request.Context(), uint(applicationID), adminID.(uint), req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Application rejected"})
+}
+
+func (c *OrganizerController) RevokeVerification(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("userId"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req dto.RevokeOrganizerRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	adminID, _ := ctx.Get("user_id")
+	err = c.organizerService.RevokeVerification(ctx.Request.Context(), uint(userID), adminID.(uint), req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Organizer verification revoked"})
+}
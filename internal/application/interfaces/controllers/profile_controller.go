@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"auth-system/internal/application/dto"
+	"auth-system/internal/application/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileController struct {
+	profileService interfaces.ProfileService
+}
+
+func NewProfileController(profileService interfaces.ProfileService) *ProfileController {
+	return &ProfileController{profileService: profileService}
+}
+
+func (c *ProfileController) GetPublicProfile(ctx *gin.Context) {
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var viewerID uint
+	if id, exists := ctx.Get("user_id"); exists {
+		viewerID = id.(uint)
+	}
+
+	profile, err := c.profileService.GetPublicProfile(ctx.Request.Context(), uint(userID), viewerID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+func (c *ProfileController) UpdateProfile(ctx *gin.Context) {
+	var req dto.UpdateProfileRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Аватар передается только в multipart-форме
+	var avatar *multipart.FileHeader
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		file, err := ctx.FormFile("avatar")
+		if err != nil && !errors.Is(err, http.ErrMissingFile) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		avatar = file
+	}
+
+	userID, _ := ctx.Get("user_id")
+	response, err := c.profileService.UpdateProfile(ctx.Request.Context(), userID.(uint), req, avatar)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (c *ProfileController) DeleteAvatar(ctx *gin.Context) {
+	userID, _ := ctx.Get("user_id")
+	response, err := c.profileService.DeleteAvatar(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"auth-system/internal/application/dto"
+	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorController struct {
+	twoFactorService interfaces.TwoFactorService
+}
+
+func NewTwoFactorController(twoFactorService interfaces.TwoFactorService) *TwoFactorController {
+	return &TwoFactorController{twoFactorService: twoFactorService}
+}
+
+func (c *TwoFactorController) GetStatus(ctx *gin.Context) {
+	userID, _ := ctx.Get("user_id")
+	status, err := c.twoFactorService.GetStatus(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+func (c *TwoFactorController) Setup(ctx *gin.Context) {
+	userID, _ := ctx.Get("user_id")
+	response, err := c.twoFactorService.Setup(ctx.Request.Context(), userID.(uint))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (c *TwoFactorController) Enable(ctx *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	response, err := c.twoFactorService.Enable(ctx.Request.Context(), userID.(uint), req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (c *TwoFactorController) Disable(ctx *gin.Context) {
+	var req dto.DisableTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	if err := c.twoFactorService.Disable(ctx.Request.Context(), userID.(uint), req, ctx.ClientIP()); err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+func (c *TwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("user_id")
+	response, err := c.twoFactorService.RegenerateRecoveryCodes(ctx.Request.Context(), userID.(uint), req, ctx.ClientIP())
+	if err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// respondTwoFactorError отдает 429 при превышении лимита попыток, остальное - 400
+func respondTwoFactorError(ctx *gin.Context, err error) {
+	var throttled *entities.LoginThrottledError
+	if errors.As(err, &throttled) {
+		respondLoginError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "context"
+
+// MailMessage письмо для отправки пользователю
+type MailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма (SMTP, файлы или память для тестов)
+type Mailer interface {
+	Send(ctx context.Context, message MailMessage) error
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"net/url"
+)
+
+// ExternalProfile данные пользователя, полученные от внешнего провайдера входа
+type ExternalProfile struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	AvatarURL     string
+}
+
+// OAuthProvider внешний провайдер входа (OIDC или OAuth2 с эндпоинтом профиля)
+type OAuthProvider interface {
+	Name() string
+	// AuthCodeURL адрес страницы согласия с state и PKCE-челленджем (S256)
+	AuthCodeURL(state, codeChallenge string) string
+	// Exchange меняет код на токен и загружает профиль; callback - остальные параметры возврата
+	Exchange(ctx context.Context, code, codeVerifier string, callback url.Values) (*ExternalProfile, error)
+}
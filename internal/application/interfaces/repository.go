@@ -3,52 +3,90 @@ package interfaces
 import (
 	"auth-system/internal/domain/entities"
 	"context"
+	"time"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, user *entities.User) error
 	FindByID(ctx context.Context, id uint) (*entities.User, error)
 	FindByEmail(ctx context.Context, email string) (*entities.User, error)
-	Update(ctx context.Context, user *entities.User) error
+	FindByUsername(ctx context.Context, username string) (*entities.User, error)
+	UpdateColumns(ctx context.Context, user *entities.User, columns ...string) error
 	UpdateLastOnline(ctx context.Context, userID uint) error
 	GetAll(ctx context.Context) ([]entities.User, error)
 	BlockUser(ctx context.Context, userID uint) error
 	UnblockUser(ctx context.Context, userID uint) error
 	GetAdmins(ctx context.Context) ([]entities.User, error)
+	GetByRoles(ctx context.Context, roles []string) ([]entities.User, error)
+	UpdateRole(ctx context.Context, userID uint, role string) error
+	GetStatus(ctx context.Context, userID uint) (*entities.UserStatus, error)
+	IncrementTokenVersion(ctx context.Context, userID uint) error
+	SetLockedUntil(ctx context.Context, userID uint, lockedUntil *time.Time) error
+	SetOrganizerVerified(ctx context.Context, userID uint, verifiedAt *time.Time) error
+	UpdateTwoFactor(ctx context.Context, userID uint, secret string, enabled bool) error
+	ConsumeTOTPStep(ctx context.Context, userID uint, step int64) (bool, error)
 }
 
 type EventRepository interface {
 	Create(ctx context.Context, event *entities.Event) error
 	FindByID(ctx context.Context, id uint) (*entities.Event, error)
 	FindAll(ctx context.Context, filter map[string]interface{}) ([]entities.Event, error)
+	GetClusters(ctx context.Context, filter map[string]interface{}, gridSize float64) ([]entities.EventCluster, error)
 	Update(ctx context.Context, event *entities.Event) error
+	Deactivate(ctx context.Context, eventID uint) error
 	Delete(ctx context.Context, id uint) error
 	GetByCreator(ctx context.Context, creatorID uint) ([]entities.Event, error)
-	GetParticipatedEvents(ctx context.Context, userID uint) ([]entities.Event, error)
+	GetParticipatedEvents(ctx context.Context, userID uint, status string) ([]entities.Event, error)
 	VerifyEvent(ctx context.Context, eventID uint) error
 	RejectEvent(ctx context.Context, eventID uint, reason string) error
 	GetPendingEvents(ctx context.Context) ([]entities.Event, error)
 	GetStatistics(ctx context.Context) (map[string]interface{}, error)
-	AddParticipant(ctx context.Context, eventID, userID uint) error
-	RemoveParticipant(ctx context.Context, eventID, userID uint) error
+	// JoinEvent устанавливает статус участия с учетом лимита мест. Ошибки: entities.ErrEventFull
+	// (если очередь не разрешена), entities.ErrAlreadyParticipating, entities.ErrAlreadyWaitlisted
+	JoinEvent(ctx context.Context, eventID, userID uint, status string, allowWaitlist bool) (string, []uint, error)
+	// LeaveEvent отменяет участие (entities.ErrNotParticipating, если записи нет)
+	// и возвращает переведенных из очереди
+	LeaveEvent(ctx context.Context, eventID, userID uint) ([]uint, error)
 	GetParticipantCount(ctx context.Context, eventID uint) (int64, error)
-	IsParticipant(ctx context.Context, eventID, userID uint) (bool, error)
+	CancelEvent(ctx context.Context, eventID uint, reason string) error
+	GetParticipantIDs(ctx context.Context, eventID uint, statuses []string) ([]uint, error)
+	GetEventParticipants(ctx context.Context, eventID uint, status string, limit, offset int) ([]entities.EventParticipant, int64, error)
+	FindParticipant(ctx context.Context, eventID, userID uint) (*entities.EventParticipant, error)
+	SetRemindersMuted(ctx context.Context, eventID, userID uint, muted bool) error
+	// GetWaitlistPosition место пользователя в очереди, начиная с 1
+	GetWaitlistPosition(ctx context.Context, eventID, userID uint) (int64, error)
+	// PromoteWaitlisted переводит из очереди столько участников, сколько есть свободных мест,
+	// и возвращает их идентификаторы
+	PromoteWaitlisted(ctx context.Context, eventID uint) ([]uint, error)
 	AddTags(ctx context.Context, eventID uint, tags []string) error
 	GetTopEvents(ctx context.Context, limit int) ([]map[string]interface{}, error)
+	AddMedia(ctx context.Context, media *entities.EventMedia) error
+	GetMedia(ctx context.Context, eventID uint) ([]entities.EventMedia, error)
+	FindMediaByID(ctx context.Context, mediaID uint) (*entities.EventMedia, error)
+	FinishMediaProcessing(ctx context.Context, media *entities.EventMedia) (bool, error)
+	GetMediaByStatus(ctx context.Context, status string) ([]entities.EventMedia, error)
+	DeleteMedia(ctx context.Context, mediaID uint) error
+	ReorderMedia(ctx context.Context, eventID uint, mediaIDs []uint) error
 }
 
 type CommentRepository interface {
 	Create(ctx context.Context, comment *entities.Comment) error
-	FindByEventID(ctx context.Context, eventID uint) ([]entities.Comment, error)
+	FindByEventID(ctx context.Context, eventID uint, maxDepth int) ([]entities.Comment, error)
+	FindThread(ctx context.Context, rootID uint, maxDepth int) ([]entities.Comment, error)
 	FindByID(ctx context.Context, id uint) (*entities.Comment, error)
 	Update(ctx context.Context, comment *entities.Comment) error
 	SoftDelete(ctx context.Context, id uint) error
 	Vote(ctx context.Context, commentID, userID uint, voteType string) error
 	GetVote(ctx context.Context, commentID, userID uint) (*entities.CommentVote, error)
 	GetScore(ctx context.Context, commentID uint) (int, error)
+	RecountVotes(ctx context.Context, commentID uint) (int, int, error)
 	DeleteVote(ctx context.Context, commentID, userID uint) error
 	UpdateVote(ctx context.Context, vote *entities.CommentVote) error
 	CreateVote(ctx context.Context, vote *entities.CommentVote) error
+	// FindByUser комментарии пользователя и, по фильтру include_replies, ответы на них
+	FindByUser(ctx context.Context, userID uint, filter map[string]interface{}, limit, offset int) ([]entities.Comment, int64, error)
+	// GetUserStats число комментариев пользователя и сумма их оценок
+	GetUserStats(ctx context.Context, userID uint) (int64, int64, error)
 }
 
 type NotificationRepository interface {
@@ -66,3 +104,59 @@ type AdminRepository interface {
 	GetEventStats(ctx context.Context) (map[string]interface{}, error)
 	GetTopEvents(ctx context.Context, limit int) ([]map[string]interface{}, error)
 }
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *entities.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (*entities.RefreshToken, error)
+	Revoke(ctx context.Context, id uint) (bool, error)
+	SetReplacedBy(ctx context.Context, id, replacedBy uint) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID uint) error
+}
+
+type VerificationTokenRepository interface {
+	Create(ctx context.Context, token *entities.VerificationToken) error
+	FindByHash(ctx context.Context, hash, purpose string) (*entities.VerificationToken, error)
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+	InvalidateForUser(ctx context.Context, userID uint, purpose string) error
+}
+
+type OrganizerApplicationRepository interface {
+	Create(ctx context.Context, application *entities.OrganizerApplication) error
+	FindByID(ctx context.Context, id uint) (*entities.OrganizerApplication, error)
+	FindLatestByUser(ctx context.Context, userID uint) (*entities.OrganizerApplication, error)
+	FindByStatus(ctx context.Context, status string) ([]entities.OrganizerApplication, error)
+	Update(ctx context.Context, application *entities.OrganizerApplication) error
+}
+
+type LoginAttemptRepository interface {
+	Create(ctx context.Context, attempt *entities.LoginAttempt) error
+	Begin(ctx context.Context, attempt *entities.LoginAttempt, since time.Time) (*entities.LoginFailures, error)
+	Finish(ctx context.Context, id uint, success bool, reason string) error
+	AccountFailures(ctx context.Context, email string, since time.Time) (int64, time.Time, error)
+	Find(ctx context.Context, filter map[string]interface{}, limit int) ([]entities.LoginAttempt, error)
+	Suspicious(ctx context.Context, since time.Time, minFailures int) ([]entities.LoginActivity, error)
+}
+
+type RecoveryCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID uint, codeHashes []string) error
+	Use(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CountUnused(ctx context.Context, userID uint) (int64, error)
+	DeleteForUser(ctx context.Context, userID uint) error
+}
+
+type ReminderRepository interface {
+	FindDue(ctx context.Context, offsetMinutes int, from, to time.Time, limit int) ([]entities.DueReminder, error)
+	Send(ctx context.Context, reminder *entities.EventReminder, notification *entities.Notification) (bool, error)
+}
+
+type IdentityRepository interface {
+	Create(ctx context.Context, identity *entities.UserIdentity) error
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error)
+	FindByUser(ctx context.Context, userID uint) ([]entities.UserIdentity, error)
+	TouchLogin(ctx context.Context, id uint) error
+	Delete(ctx context.Context, userID uint, provider string) error
+	CreateState(ctx context.Context, state *entities.OAuthState) error
+	ConsumeState(ctx context.Context, stateHash string) (*entities.OAuthState, error)
+	DeleteExpiredStates(ctx context.Context) error
+}
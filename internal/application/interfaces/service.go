@@ -2,35 +2,83 @@ package interfaces
 
 import (
 	"auth-system/internal/application/dto"
+	"auth-system/internal/domain/entities"
 	"context"
+	"mime/multipart"
 )
 
 type AuthService interface {
 	Register(ctx context.Context, req dto.RegisterRequest) (*dto.AuthResponse, error)
-	Login(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error)
+	Login(ctx context.Context, req dto.LoginRequest, clientIP string) (*dto.AuthResponse, error)
+	LoginTwoFactor(ctx context.Context, req dto.TwoFactorLoginRequest, clientIP string) (*dto.AuthResponse, error)
+	RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (*dto.AuthResponse, error)
+	Logout(ctx context.Context, req dto.RefreshTokenRequest) error
+	VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, userID uint) error
+	ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, userID uint, req dto.ChangePasswordRequest) (*dto.AuthResponse, error)
+	ConfirmEmailChange(ctx context.Context, req dto.VerifyEmailRequest) error
 	GetProfile(ctx context.Context, userID uint) (*dto.UserResponse, error)
 	UpdateLastOnline(ctx context.Context, userID uint) error
 }
 
+type ProfileService interface {
+	GetPublicProfile(ctx context.Context, userID, viewerID uint) (*dto.PublicProfileResponse, error)
+	UpdateProfile(ctx context.Context, userID uint, req dto.UpdateProfileRequest, avatar *multipart.FileHeader) (*dto.UpdateProfileResponse, error)
+	DeleteAvatar(ctx context.Context, userID uint) (*dto.UserResponse, error)
+}
+
+type OAuthService interface {
+	Providers() []string
+	StartLogin(ctx context.Context, providerName string) (*dto.OAuthStartResponse, error)
+	StartLink(ctx context.Context, providerName string, userID uint) (*dto.OAuthStartResponse, error)
+	Callback(ctx context.Context, providerName string, req dto.OAuthCallbackRequest, clientIP string) (*dto.AuthResponse, error)
+	GetIdentities(ctx context.Context, userID uint) ([]dto.IdentityResponse, error)
+	Unlink(ctx context.Context, userID uint, providerName string) error
+}
+
+type TwoFactorService interface {
+	GetStatus(ctx context.Context, userID uint) (*dto.TwoFactorStatusResponse, error)
+	Setup(ctx context.Context, userID uint) (*dto.TwoFactorSetupResponse, error)
+	Enable(ctx context.Context, userID uint, req dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, userID uint, req dto.DisableTwoFactorRequest, clientIP string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, req dto.TwoFactorCodeRequest, clientIP string) (*dto.RecoveryCodesResponse, error)
+}
+
 type EventService interface {
 	CreateEvent(ctx context.Context, req dto.CreateEventRequest, userID uint) (*dto.EventResponse, error)
 	GetEvents(ctx context.Context, filter dto.EventFilter) ([]dto.EventResponse, error)
 	GetEventByID(ctx context.Context, id uint) (*dto.EventResponse, error)
 	UpdateEvent(ctx context.Context, id uint, req dto.UpdateEventRequest, userID uint) (*dto.EventResponse, error)
 	DeleteEvent(ctx context.Context, id uint, userID uint) error
-	Participate(ctx context.Context, eventID, userID uint) error
+	CancelEvent(ctx context.Context, eventID, userID uint, req dto.CancelEventRequest) (*dto.EventResponse, error)
+	SetReminders(ctx context.Context, eventID, userID uint, req dto.RemindersRequest) (*dto.ParticipationResponse, error)
+	Participate(ctx context.Context, eventID, userID uint, query dto.ParticipateQuery) (*dto.ParticipationResponse, error)
+	GetParticipation(ctx context.Context, eventID, userID uint) (*dto.ParticipationResponse, error)
+	GetEventParticipants(ctx context.Context, eventID, userID uint, query dto.ParticipantsQuery) (*dto.EventParticipantsResponse, error)
+	ExportParticipants(ctx context.Context, eventID, userID uint, query dto.ParticipantsExportQuery) (*dto.ExportFile, error)
 	CancelParticipation(ctx context.Context, eventID, userID uint) error
 	GetUserEvents(ctx context.Context, userID uint) ([]dto.EventResponse, error)
-	GetParticipatedEvents(ctx context.Context, userID uint) ([]dto.EventResponse, error)
+	GetParticipatedEvents(ctx context.Context, userID uint, query dto.ParticipatedEventsQuery) ([]dto.EventResponse, error)
 	FilterEvents(ctx context.Context, filter dto.EventFilter) ([]dto.EventResponse, error)
+	GetClusters(ctx context.Context, req dto.ClusterRequest) (*dto.ClusterResponse, error)
+}
+
+type MediaService interface {
+	UploadMedia(ctx context.Context, eventID, userID uint, files []*multipart.FileHeader) ([]dto.Media, error)
+	ReorderMedia(ctx context.Context, eventID, userID uint, req dto.ReorderMediaRequest) ([]dto.Media, error)
+	DeleteMedia(ctx context.Context, eventID, mediaID, userID uint) error
 }
 
 type CommentService interface {
 	CreateComment(ctx context.Context, req dto.CreateCommentRequest, eventID, userID uint) (*dto.CommentResponse, error)
-	GetComments(ctx context.Context, eventID uint) ([]dto.CommentResponse, error)
+	GetComments(ctx context.Context, eventID uint, query dto.CommentTreeQuery) ([]dto.CommentResponse, error)
+	GetThread(ctx context.Context, commentID uint, query dto.CommentTreeQuery) ([]dto.CommentResponse, error)
 	UpdateComment(ctx context.Context, commentID uint, req dto.UpdateCommentRequest, userID uint) (*dto.CommentResponse, error)
 	DeleteComment(ctx context.Context, commentID, userID uint) error
 	VoteComment(ctx context.Context, commentID, userID uint, voteType string) (*dto.CommentResponse, error)
+	GetUserComments(ctx context.Context, userID uint, query dto.UserCommentsQuery) (*dto.UserCommentsResponse, error)
 }
 
 type NotificationService interface {
@@ -48,8 +96,27 @@ type AdminService interface {
 	DeleteEvent(ctx context.Context, eventID, adminID uint) error
 	BlockUser(ctx context.Context, userID, adminID uint) error
 	UnblockUser(ctx context.Context, userID, adminID uint) error
+	UnlockUser(ctx context.Context, userID, adminID uint) error
+	GetLoginAttempts(ctx context.Context, query dto.LoginAttemptQuery) ([]dto.LoginAttemptResponse, error)
+	GetSuspiciousLogins(ctx context.Context, query dto.SuspiciousLoginQuery) ([]dto.LoginActivityResponse, error)
+	ChangeUserRole(ctx context.Context, userID, adminID uint, req dto.ChangeRoleRequest) error
 	DeleteComment(ctx context.Context, commentID, adminID uint) error
 	GetAllEvents(ctx context.Context) ([]dto.EventResponse, error)
 	GetAllUsers(ctx context.Context) ([]dto.UserResponse, error)
 	GetPendingEvents(ctx context.Context) ([]dto.EventResponse, error)
 }
+
+type OrganizerService interface {
+	Apply(ctx context.Context, userID uint, req dto.OrganizerApplicationRequest) (*dto.OrganizerApplicationResponse, error)
+	GetMyApplication(ctx context.Context, userID uint) (*dto.OrganizerApplicationResponse, error)
+	GetApplications(ctx context.Context, query dto.OrganizerApplicationQuery) ([]dto.OrganizerApplicationResponse, error)
+	ApproveApplication(ctx context.Context, applicationID, adminID uint, req dto.ReviewApplicationRequest) error
+	RejectApplication(ctx context.Context, applicationID, adminID uint, req dto.ReviewApplicationRequest) error
+	RevokeVerification(ctx context.Context, userID, adminID uint, req dto.RevokeOrganizerRequest) error
+}
+
+// AccessChecker проверяет статус аккаунта для уже подписанного токена
+type AccessChecker interface {
+	CheckAccess(ctx context.Context, userID uint, tokenVersion int) (*entities.UserStatus, error)
+	Invalidate(userID uint)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+)
+
+// MediaStorage хранилище загруженных файлов (MinIO/S3 или локальная папка)
+type MediaStorage interface {
+	// Upload сохраняет файл под ключом key и возвращает его публичный URL
+	Upload(ctx context.Context, key string, content io.Reader, size int64, contentType string) (string, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+)
+
+type cachedUserStatus struct {
+	status    entities.UserStatus
+	expiresAt time.Time
+}
+
+// AccessService проверяет, что токен все еще действителен для аккаунта.
+// Статусы пользователей кешируются и сбрасываются при блокировке или смене роли.
+type AccessService struct {
+	userRepo appInterfaces.UserRepository
+	ttl      time.Duration
+	mu       sync.RWMutex
+	cache    map[uint]cachedUserStatus
+	// generation растет при каждом Invalidate: статус, прочитанный из БД до сброса,
+	// уже может быть устаревшим, и в кеш он не попадает
+	generation uint64
+}
+
+func NewAccessService(userRepo appInterfaces.UserRepository, ttl time.Duration) *AccessService {
+	return &AccessService{
+		userRepo: userRepo,
+		ttl:      ttl,
+		cache:    make(map[uint]cachedUserStatus),
+	}
+}
+
+func (s *AccessService) CheckAccess(ctx context.Context, userID uint, tokenVersion int) (*entities.UserStatus, error) {
+	status, err := s.getStatus(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if status.IsBlocked {
+		return nil, errors.New("account is blocked")
+	}
+
+	// Токены, выпущенные до блокировки или смены роли, больше не принимаются
+	if status.TokenVersion != tokenVersion {
+		return nil, errors.New("token has been revoked")
+	}
+
+	return status, nil
+}
+
+// Invalidate сбрасывает закешированный статус пользователя
+func (s *AccessService) Invalidate(userID uint) {
+	s.mu.Lock()
+	delete(s.cache, userID)
+	s.generation++
+	s.mu.Unlock()
+}
+
+func (s *AccessService) getStatus(ctx context.Context, userID uint) (*entities.UserStatus, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[userID]
+	generation := s.generation
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		status := cached.status
+		return &status, nil
+	}
+
+	status, err := s.userRepo.GetStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.cache[userID] = cachedUserStatus{status: *status, expiresAt: time.Now().Add(s.ttl)}
+	}
+	s.mu.Unlock()
+
+	return status, nil
+}
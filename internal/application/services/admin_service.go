@@ -16,6 +16,9 @@ type AdminService struct {
 	userRepo         appInterfaces.UserRepository
 	commentRepo      appInterfaces.CommentRepository
 	notificationRepo appInterfaces.NotificationRepository
+	refreshTokenRepo appInterfaces.RefreshTokenRepository
+	loginAttemptRepo appInterfaces.LoginAttemptRepository
+	accessChecker    appInterfaces.AccessChecker
 }
 
 func NewAdminService(
@@ -24,6 +27,9 @@ func NewAdminService(
 	userRepo appInterfaces.UserRepository,
 	commentRepo appInterfaces.CommentRepository,
 	notificationRepo appInterfaces.NotificationRepository,
+	refreshTokenRepo appInterfaces.RefreshTokenRepository,
+	loginAttemptRepo appInterfaces.LoginAttemptRepository,
+	accessChecker appInterfaces.AccessChecker,
 ) *AdminService {
 	return &AdminService{
 		adminRepo:        adminRepo,
@@ -31,6 +37,9 @@ func NewAdminService(
 		userRepo:         userRepo,
 		commentRepo:      commentRepo,
 		notificationRepo: notificationRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		accessChecker:    accessChecker,
 	}
 }
 
@@ -98,6 +107,10 @@ func (s *AdminService) GetStatistics(ctx context.Context) (*dto.StatisticsRespon
 }
 
 func (s *AdminService) VerifyEvent(ctx context.Context, eventID, adminID uint) error {
+	if _, err := authorize(ctx, s.userRepo, adminID, entities.PermEventsVerify); err != nil {
+		return err
+	}
+
 	event, err := s.eventRepo.FindByID(ctx, eventID)
 	if err != nil {
 		return errors.New("event not found")
@@ -107,8 +120,7 @@ func (s *AdminService) VerifyEvent(ctx context.Context, eventID, adminID uint) e
 		return errors.New("event is not active")
 	}
 
-	event.IsVerified = true
-	if err := s.eventRepo.Update(ctx, event); err != nil {
+	if err := s.eventRepo.VerifyEvent(ctx, eventID); err != nil {
 		return err
 	}
 
@@ -136,6 +148,10 @@ func (s *AdminService) VerifyEvent(ctx context.Context, eventID, adminID uint) e
 }
 
 func (s *AdminService) RejectEvent(ctx context.Context, eventID, adminID uint, reason string) error {
+	if _, err := authorize(ctx, s.userRepo, adminID, entities.PermEventsVerify); err != nil {
+		return err
+	}
+
 	event, err := s.eventRepo.FindByID(ctx, eventID)
 	if err != nil {
 		return errors.New("event not found")
@@ -145,8 +161,7 @@ func (s *AdminService) RejectEvent(ctx context.Context, eventID, adminID uint, r
 		return errors.New("event is not active")
 	}
 
-	event.IsActive = false
-	if err := s.eventRepo.Update(ctx, event); err != nil {
+	if err := s.eventRepo.RejectEvent(ctx, eventID, reason); err != nil {
 		return err
 	}
 
@@ -175,13 +190,16 @@ func (s *AdminService) RejectEvent(ctx context.Context, eventID, adminID uint, r
 }
 
 func (s *AdminService) DeleteEvent(ctx context.Context, eventID, adminID uint) error {
+	if _, err := authorize(ctx, s.userRepo, adminID, entities.PermEventsDelete); err != nil {
+		return err
+	}
+
 	event, err := s.eventRepo.FindByID(ctx, eventID)
 	if err != nil {
 		return errors.New("event not found")
 	}
 
-	event.IsActive = false
-	if err := s.eventRepo.Update(ctx, event); err != nil {
+	if err := s.eventRepo.Deactivate(ctx, eventID); err != nil {
 		return err
 	}
 
@@ -209,19 +227,41 @@ func (s *AdminService) DeleteEvent(ctx context.Context, eventID, adminID uint) e
 }
 
 func (s *AdminService) BlockUser(ctx context.Context, userID, adminID uint) error {
+	if userID == adminID {
+		return errors.New("cannot block yourself")
+	}
+
+	actor, err := authorize(ctx, s.userRepo, adminID, entities.PermUsersBlock)
+	if err != nil {
+		return err
+	}
+
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return errors.New("user not found")
 	}
 
+	// Блокировать можно только младшие роли, как и менять их
+	if !entities.CanManageRole(actor.Role, user.Role) {
+		return errors.New("insufficient permissions")
+	}
+
 	if user.IsBlocked {
 		return errors.New("user is already blocked")
 	}
 
-	user.IsBlocked = true
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	if err := s.userRepo.BlockUser(ctx, userID); err != nil {
+		return err
+	}
+
+	// Отзываем выданные токены, блокировка действует сразу
+	if err := s.userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, userID); err != nil {
 		return err
 	}
+	s.accessChecker.Invalidate(userID)
 
 	// Логируем действие
 	action := &entities.AdminAction{
@@ -247,19 +287,32 @@ func (s *AdminService) BlockUser(ctx context.Context, userID, adminID uint) erro
 }
 
 func (s *AdminService) UnblockUser(ctx context.Context, userID, adminID uint) error {
+	if userID == adminID {
+		return errors.New("cannot unblock yourself")
+	}
+
+	actor, err := authorize(ctx, s.userRepo, adminID, entities.PermUsersBlock)
+	if err != nil {
+		return err
+	}
+
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return errors.New("user not found")
 	}
 
+	if !entities.CanManageRole(actor.Role, user.Role) {
+		return errors.New("insufficient permissions")
+	}
+
 	if !user.IsBlocked {
 		return errors.New("user is not blocked")
 	}
 
-	user.IsBlocked = false
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	if err := s.userRepo.UnblockUser(ctx, userID); err != nil {
 		return err
 	}
+	s.accessChecker.Invalidate(userID)
 
 	// Логируем действие
 	action := &entities.AdminAction{
@@ -274,7 +327,212 @@ func (s *AdminService) UnblockUser(ctx context.Context, userID, adminID uint) er
 	return nil
 }
 
+// UnlockUser досрочно снимает блокировку после неудачных попыток входа
+func (s *AdminService) UnlockUser(ctx context.Context, userID, adminID uint) error {
+	if _, err := authorize(ctx, s.userRepo, adminID, entities.PermUsersBlock); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !user.IsLocked() {
+		return errors.New("user is not locked")
+	}
+
+	if err := s.userRepo.SetLockedUntil(ctx, userID, nil); err != nil {
+		return err
+	}
+
+	action := &entities.AdminAction{
+		AdminID:     adminID,
+		ActionType:  "unlock_user",
+		TargetID:    userID,
+		TargetType:  "user",
+		PerformedAt: time.Now(),
+	}
+	s.adminRepo.LogAction(ctx, action)
+
+	return nil
+}
+
+func (s *AdminService) GetLoginAttempts(ctx context.Context, query dto.LoginAttemptQuery) ([]dto.LoginAttemptResponse, error) {
+	filter := make(map[string]interface{})
+	if query.Email != "" {
+		filter["email"] = normalizeEmail(query.Email)
+	}
+	if query.IP != "" {
+		filter["ip"] = query.IP
+	}
+	if query.UserID != 0 {
+		filter["user_id"] = query.UserID
+	}
+	if query.FailedOnly {
+		filter["failed_only"] = true
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = 100
+	}
+
+	attempts, err := s.loginAttemptRepo.Find(ctx, filter, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.LoginAttemptResponse, len(attempts))
+	for i, attempt := range attempts {
+		response[i] = dto.LoginAttemptResponse{
+			ID:        attempt.ID,
+			UserID:    attempt.UserID,
+			Email:     attempt.Email,
+			IP:        attempt.IP,
+			Success:   attempt.Success,
+			Reason:    attempt.Reason,
+			CreatedAt: attempt.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return response, nil
+}
+
+// GetSuspiciousLogins возвращает IP-адреса с большим числом неудачных входов
+func (s *AdminService) GetSuspiciousLogins(ctx context.Context, query dto.SuspiciousLoginQuery) ([]dto.LoginActivityResponse, error) {
+	hours := query.Hours
+	if hours == 0 {
+		hours = 24
+	}
+	minFailures := query.MinFailures
+	if minFailures == 0 {
+		minFailures = 10
+	}
+
+	activity, err := s.loginAttemptRepo.Suspicious(ctx, time.Now().Add(-time.Duration(hours)*time.Hour), minFailures)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.LoginActivityResponse, len(activity))
+	for i, item := range activity {
+		response[i] = dto.LoginActivityResponse{
+			IP:          item.IP,
+			Failures:    item.Failures,
+			Accounts:    item.Accounts,
+			LastAttempt: item.LastAttempt.Format(time.RFC3339),
+		}
+	}
+
+	return response, nil
+}
+
+// ChangeUserRole повышает или понижает пользователя; каждое изменение попадает в admin_actions
+func (s *AdminService) ChangeUserRole(ctx context.Context, userID, adminID uint, req dto.ChangeRoleRequest) error {
+	if userID == adminID {
+		return errors.New("cannot change your own role")
+	}
+
+	actor, err := authorize(ctx, s.userRepo, adminID, entities.PermUsersManageRoles)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if user.Role == req.Role {
+		return errors.New("user already has this role")
+	}
+
+	// Нельзя трогать равных или старших и выдавать роль не ниже своей
+	if !entities.CanManageRole(actor.Role, user.Role) || !entities.CanManageRole(actor.Role, req.Role) {
+		return errors.New("insufficient permissions")
+	}
+
+	previousRole := user.Role
+	if err := s.userRepo.UpdateRole(ctx, userID, req.Role); err != nil {
+		return err
+	}
+	s.accessChecker.Invalidate(userID)
+
+	reason := previousRole + " -> " + req.Role
+	if req.Reason != "" {
+		reason += ": " + req.Reason
+	}
+	action := &entities.AdminAction{
+		AdminID:     adminID,
+		ActionType:  "change_role",
+		TargetID:    userID,
+		TargetType:  "user",
+		Reason:      reason,
+		PerformedAt: time.Now(),
+	}
+	s.adminRepo.LogAction(ctx, action)
+
+	// Уведомляем пользователя
+	message := "Ваша роль изменена администратором на \"" + req.Role + "\""
+	s.notificationRepo.Create(ctx, &entities.Notification{
+		UserID:    userID,
+		Message:   message,
+		Type:      "system",
+		Read:      false,
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+// authorize проверяет право по актуальной роли из БД, не полагаясь только на middleware
+func authorize(ctx context.Context, userRepo appInterfaces.UserRepository, actorID uint, permission entities.Permission) (*entities.User, error) {
+	actor, err := userRepo.FindByID(ctx, actorID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if actor.IsBlocked || !actor.Can(permission) {
+		return nil, errors.New("insufficient permissions")
+	}
+	return actor, nil
+}
+
+// BootstrapAdmin назначает первого суперадминистратора; работает, только пока администраторов нет
+func (s *AdminService) BootstrapAdmin(ctx context.Context, email string) error {
+	admins, err := s.userRepo.GetAdmins(ctx)
+	if err != nil {
+		return err
+	}
+	if len(admins) > 0 {
+		return errors.New("administrator already exists")
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, user.ID, entities.RoleSuperAdmin); err != nil {
+		return err
+	}
+
+	action := &entities.AdminAction{
+		AdminID:     user.ID,
+		ActionType:  "change_role",
+		TargetID:    user.ID,
+		TargetType:  "user",
+		Reason:      user.Role + " -> " + entities.RoleSuperAdmin + ": bootstrap",
+		PerformedAt: time.Now(),
+	}
+	return s.adminRepo.LogAction(ctx, action)
+}
+
 func (s *AdminService) DeleteComment(ctx context.Context, commentID, adminID uint) error {
+	if _, err := authorize(ctx, s.userRepo, adminID, entities.PermCommentsDelete); err != nil {
+		return err
+	}
+
 	comment, err := s.commentRepo.FindByID(ctx, commentID)
 	if err != nil {
 		return errors.New("comment not found")
@@ -336,14 +594,15 @@ func (s *AdminService) GetAllUsers(ctx context.Context) ([]dto.UserResponse, err
 	response := make([]dto.UserResponse, len(users))
 	for i, user := range users {
 		response[i] = dto.UserResponse{
-			ID:         user.ID,
-			Username:   user.Username,
-			Email:      user.Email,
-			Role:       user.Role,
-			AvatarURL:  user.AvatarURL,
-			IsBlocked:  user.IsBlocked,
-			LastOnline: user.LastOnline.Format(time.RFC3339),
-			CreatedAt:  user.CreatedAt.Format(time.RFC3339),
+			ID:          user.ID,
+			Username:    user.Username,
+			Email:       user.Email,
+			Role:        user.Role,
+			AvatarURL:   user.AvatarURL,
+			IsBlocked:   user.IsBlocked,
+			LockedUntil: formatOptionalTime(user.LockedUntil),
+			LastOnline:  user.LastOnline.Format(time.RFC3339),
+			CreatedAt:   user.CreatedAt.Format(time.RFC3339),
 		}
 	}
 
@@ -378,35 +637,29 @@ func (s *AdminService) eventToDTO(event *entities.Event) *dto.EventResponse {
 	// Преобразуем медиа
 	media := make([]dto.Media, len(event.Media))
 	for i, m := range event.Media {
-		media[i] = dto.Media{
-			ID:         m.ID,
-			FileURL:    m.FileURL,
-			FileType:   m.FileType,
-			OrderIndex: m.OrderIndex,
-		}
+		media[i] = mediaToDTO(&m)
 	}
 
 	return &dto.EventResponse{
-		ID:              event.ID,
-		Title:           event.Title,
-		Description:     event.Description,
-		EventDate:       event.EventDate,
-		Latitude:        event.Latitude,
-		Longitude:       event.Longitude,
-		Type:            event.Type,
-		MaxParticipants: event.MaxParticipants,
-		Price:           event.Price,
-		Address:         event.Address,
-		IsVerified:      event.IsVerified,
-		IsActive:        event.IsActive,
-		CreatorID:       event.CreatorID,
-		Creator: dto.UserShort{
-			ID:       event.Creator.ID,
-			Username: event.Creator.Username,
-			Email:    event.Creator.Email,
-			Role:     event.Creator.Role,
-		},
+		ID:                event.ID,
+		Title:             event.Title,
+		Description:       event.Description,
+		EventDate:         event.EventDate,
+		Latitude:          event.Latitude,
+		Longitude:         event.Longitude,
+		Type:              event.Type,
+		MaxParticipants:   event.MaxParticipants,
+		Price:             event.Price,
+		Address:           event.Address,
+		IsVerified:        event.IsVerified,
+		IsActive:          event.IsActive,
+		IsCancelled:       event.IsCancelled(),
+		CancelledAt:       event.CancelledAt,
+		CancelReason:      event.CancelReason,
+		CreatorID:         event.CreatorID,
+		Creator:           userToShort(&event.Creator),
 		ParticipantsCount: event.ParticipantsCount,
+		RSVPCounts:        event.RSVPCounts,
 		CreatedAt:         event.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:         event.UpdatedAt.Format(time.RFC3339),
 		Tags:              tags,
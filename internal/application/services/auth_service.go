@@ -3,6 +3,10 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"auth-system/internal/application/dto"
@@ -11,17 +15,51 @@ import (
 	"auth-system/internal/pkg/utils"
 )
 
+// AuthSettings сроки жизни токенов и адрес фронтенда для ссылок в письмах
+type AuthSettings struct {
+	RefreshTTL            time.Duration
+	EmailVerificationTTL  time.Duration
+	PasswordResetTokenTTL time.Duration
+	LoginChallengeTTL     time.Duration
+	AppURL                string
+}
+
 type AuthService struct {
-	userRepo     appInterfaces.UserRepository
-	jwtUtil      utils.JWTUtil
-	passwordUtil utils.PasswordUtil
+	userRepo         appInterfaces.UserRepository
+	refreshTokenRepo appInterfaces.RefreshTokenRepository
+	verificationRepo appInterfaces.VerificationTokenRepository
+	jwtUtil          utils.JWTUtil
+	passwordUtil     utils.PasswordUtil
+	mailer           appInterfaces.Mailer
+	accessChecker    appInterfaces.AccessChecker
+	loginGuard       *LoginGuard
+	twoFactor        *TwoFactorService
+	settings         AuthSettings
 }
 
-func NewAuthService(userRepo appInterfaces.UserRepository, jwtUtil utils.JWTUtil, passwordUtil utils.PasswordUtil) *AuthService {
+func NewAuthService(
+	userRepo appInterfaces.UserRepository,
+	refreshTokenRepo appInterfaces.RefreshTokenRepository,
+	verificationRepo appInterfaces.VerificationTokenRepository,
+	jwtUtil utils.JWTUtil,
+	passwordUtil utils.PasswordUtil,
+	mailer appInterfaces.Mailer,
+	accessChecker appInterfaces.AccessChecker,
+	loginGuard *LoginGuard,
+	twoFactor *TwoFactorService,
+	settings AuthSettings,
+) *AuthService {
 	return &AuthService{
-		userRepo:     userRepo,
-		jwtUtil:      jwtUtil,
-		passwordUtil: passwordUtil,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		verificationRepo: verificationRepo,
+		jwtUtil:          jwtUtil,
+		passwordUtil:     passwordUtil,
+		mailer:           mailer,
+		accessChecker:    accessChecker,
+		loginGuard:       loginGuard,
+		twoFactor:        twoFactor,
+		settings:         settings,
 	}
 }
 
@@ -38,18 +76,12 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 		return nil, err
 	}
 
-	// Validate role
-	role := req.Role
-	if role != "user" && role != "admin" {
-		role = "user"
-	}
-
-	// Create user
+	// Create user: роль при регистрации всегда обычная, повышает только администратор
 	user := &entities.User{
 		Username:     req.Username,
 		Email:        req.Email,
 		PasswordHash: hashedPassword,
-		Role:         role,
+		Role:         entities.RoleUser,
 		LastOnline:   time.Now(),
 		CreatedAt:    time.Now(),
 		UpdatedAt:    time.Now(),
@@ -59,67 +91,545 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 		return nil, err
 	}
 
-	// Generate token
-	token, err := s.jwtUtil.GenerateToken(user.ID, user.Email, user.Role)
-	if err != nil {
-		return nil, err
+	// Письмо не должно ломать регистрацию: его можно запросить повторно
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		log.Printf("failed to send verification email to user %d: %v", user.ID, err)
 	}
 
-	return &dto.AuthResponse{
-		Token: token,
-		User: dto.UserResponse{
-			ID:         user.ID,
-			Username:   user.Username,
-			Email:      user.Email,
-			Role:       user.Role,
-			AvatarURL:  user.AvatarURL,
-			IsBlocked:  user.IsBlocked,
-			LastOnline: user.LastOnline.Format(time.RFC3339),
-			CreatedAt:  user.CreatedAt.Format(time.RFC3339),
-		},
-	}, nil
-}
-
-func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
-	// Find user
+	// Выдаем пару токенов для новой сессии
+	return s.issueTokens(ctx, user, "")
+}
+
+func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest, clientIP string) (*dto.AuthResponse, error) {
+	// Find user; несуществующий аккаунт тоже учитывается в счетчиках
 	user, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
+		user = nil
+	}
+
+	// Задержка после серии неудач и временная блокировка
+	attemptID, err := s.loginGuard.Begin(ctx, req.Email, clientIP, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		s.loginGuard.Fail(ctx, attemptID, req.Email, nil, entities.LoginReasonInvalidCredentials)
+		return nil, errors.New("invalid credentials")
+	}
+
+	// Check password
+	if !s.passwordUtil.CheckPasswordHash(req.Password, user.PasswordHash) {
+		s.loginGuard.Fail(ctx, attemptID, req.Email, user, entities.LoginReasonInvalidCredentials)
 		return nil, errors.New("invalid credentials")
 	}
 
 	// Check if user is blocked
 	if user.IsBlocked {
+		s.loginGuard.Fail(ctx, attemptID, req.Email, user, entities.LoginReasonBlocked)
 		return nil, errors.New("account is blocked")
 	}
 
-	// Check password
-	if !s.passwordUtil.CheckPasswordHash(req.Password, user.PasswordHash) {
-		return nil, errors.New("invalid credentials")
+	return s.beginSession(ctx, user, req.Email, clientIP, attemptID)
+}
+
+// beginSession вызывается после проверки первого фактора (пароля или внешнего провайдера):
+// при включенной 2FA возвращает только challenge-токен. attemptID - попытка из LoginGuard.Begin,
+// 0 для входа через провайдера
+func (s *AuthService) beginSession(ctx context.Context, user *entities.User, email, clientIP string, attemptID uint) (*dto.AuthResponse, error) {
+	if user.TOTPEnabled {
+		challenge, err := s.createToken(ctx, user.ID, user.Email, entities.TokenPurposeLoginChallenge, s.settings.LoginChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		if attemptID != 0 {
+			s.loginGuard.PassFirstFactor(ctx, attemptID)
+		}
+		return &dto.AuthResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+	}
+
+	return s.completeLogin(ctx, user, email, clientIP, attemptID)
+}
+
+// LoginTwoFactor второй шаг входа: обменивает challenge-токен и код на пару токенов
+func (s *AuthService) LoginTwoFactor(ctx context.Context, req dto.TwoFactorLoginRequest, clientIP string) (*dto.AuthResponse, error) {
+	challenge, err := s.verificationRepo.FindByHash(ctx, utils.HashToken(req.ChallengeToken), entities.TokenPurposeLoginChallenge)
+	if err != nil || challenge.IsUsed() || challenge.IsExpired() {
+		return nil, errors.New("invalid or expired challenge")
 	}
 
-	// Update last online
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, errors.New("invalid or expired challenge")
+	}
+
+	// Подбор кодов ограничивается теми же счетчиками, что и подбор пароля
+	attemptID, err := s.loginGuard.Begin(ctx, challenge.Email, clientIP, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsBlocked {
+		s.loginGuard.Fail(ctx, attemptID, challenge.Email, user, entities.LoginReasonBlocked)
+		return nil, errors.New("account is blocked")
+	}
+
+	ok, err := s.twoFactor.VerifyCode(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.loginGuard.Fail(ctx, attemptID, challenge.Email, user, entities.LoginReasonInvalidOTP)
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	used, err := s.verificationRepo.MarkUsed(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errors.New("invalid or expired challenge")
+	}
+
+	return s.completeLogin(ctx, user, challenge.Email, clientIP, attemptID)
+}
+
+// completeLogin фиксирует успешный вход и открывает новую сессию
+func (s *AuthService) completeLogin(ctx context.Context, user *entities.User, email, clientIP string, attemptID uint) (*dto.AuthResponse, error) {
+	s.loginGuard.Succeed(ctx, attemptID, email, clientIP, user)
+
+	// Update last online; истекшая блокировка снимается. Пишем только эти колонки:
+	// копия пользователя прочитана до проверки пароля и могла устареть
 	user.LastOnline = time.Now()
-	s.userRepo.Update(ctx, user)
+	user.LockedUntil = nil
+	if err := s.userRepo.UpdateLastOnline(ctx, user.ID); err != nil {
+		log.Printf("failed to update last online of user %d: %v", user.ID, err)
+	}
+	if err := s.userRepo.SetLockedUntil(ctx, user.ID, nil); err != nil {
+		log.Printf("failed to clear lock of user %d: %v", user.ID, err)
+	}
+
+	// Выдаем пару токенов для новой сессии
+	response, err := s.issueTokens(ctx, user, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Администратор без второго фактора попадет в админку только после подключения TOTP
+	response.TwoFactorSetupRequired = entities.RequiresTwoFactor(user.Role) && !user.TOTPEnabled
+	return response, nil
+}
 
-	// Generate token
-	token, err := s.jwtUtil.GenerateToken(user.ID, user.Email, user.Role)
+// RefreshToken обменивает refresh-токен на новую пару токенов (ротация)
+func (s *AuthService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (*dto.AuthResponse, error) {
+	token, err := s.refreshTokenRepo.FindByHash(ctx, utils.HashToken(req.RefreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// Повторное использование уже замененного токена - признак кражи, отзываем всю сессию
+	if token.IsRevoked() {
+		s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+		return nil, errors.New("refresh token reuse detected")
+	}
+
+	if token.IsExpired() {
+		return nil, errors.New("refresh token expired")
+	}
+
+	// Отзыв атомарный: из двух параллельных запросов с одним токеном пройдет только один
+	revoked, err := s.refreshTokenRepo.Revoke(ctx, token.ID)
 	if err != nil {
 		return nil, err
 	}
+	if !revoked {
+		s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+		return nil, errors.New("refresh token reuse detected")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.IsBlocked {
+		s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+		return nil, errors.New("account is blocked")
+	}
+
+	response, newTokenID, err := s.issueTokenPair(ctx, user, token.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	s.refreshTokenRepo.SetReplacedBy(ctx, token.ID, newTokenID)
+
+	return response, nil
+}
+
+// Logout завершает сессию, к которой относится refresh-токен
+func (s *AuthService) Logout(ctx context.Context, req dto.RefreshTokenRequest) error {
+	token, err := s.refreshTokenRepo.FindByHash(ctx, utils.HashToken(req.RefreshToken))
+	if err != nil {
+		return errors.New("invalid refresh token")
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+}
+
+// issueTokens выдает access- и refresh-токены; пустой familyID открывает новую сессию
+func (s *AuthService) issueTokens(ctx context.Context, user *entities.User, familyID string) (*dto.AuthResponse, error) {
+	response, _, err := s.issueTokenPair(ctx, user, familyID)
+	return response, err
+}
+
+func (s *AuthService) issueTokenPair(ctx context.Context, user *entities.User, familyID string) (*dto.AuthResponse, uint, error) {
+	accessToken, err := s.jwtUtil.GenerateToken(user.ID, user.Email, user.Role, user.TokenVersion)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rawRefresh, refreshHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, 0, err
+	}
 
+	if familyID == "" {
+		familyID, _, err = utils.GenerateOpaqueToken()
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	refreshToken := &entities.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: refreshHash,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(s.settings.RefreshTTL),
+		CreatedAt: time.Now(),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, refreshToken); err != nil {
+		return nil, 0, err
+	}
+
+	userDTO := userToDTO(user)
 	return &dto.AuthResponse{
-		Token: token,
-		User: dto.UserResponse{
-			ID:         user.ID,
-			Username:   user.Username,
-			Email:      user.Email,
-			Role:       user.Role,
-			AvatarURL:  user.AvatarURL,
-			IsBlocked:  user.IsBlocked,
-			LastOnline: user.LastOnline.Format(time.RFC3339),
-			CreatedAt:  user.CreatedAt.Format(time.RFC3339),
-		},
-	}, nil
+		Token:        accessToken,
+		RefreshToken: rawRefresh,
+		ExpiresAt:    time.Now().Add(s.jwtUtil.AccessTTL()).Format(time.RFC3339),
+		User:         &userDTO,
+	}, refreshToken.ID, nil
+}
+
+// VerifyEmail подтверждает почту по токену из письма
+func (s *AuthService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) error {
+	token, err := s.consumeToken(ctx, req.Token, entities.TokenPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	// Токен выписан на конкретный адрес; после смены почты старые ссылки недействительны
+	if !strings.EqualFold(user.Email, token.Email) {
+		return errors.New("invalid or expired token")
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	user.EmailVerified = true
+	user.UpdatedAt = time.Now()
+	return s.userRepo.UpdateColumns(ctx, user, "email_verified", "updated_at")
+}
+
+// ResendVerification повторно отправляет письмо с подтверждением; прежние ссылки перестают работать
+func (s *AuthService) ResendVerification(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if user.EmailVerified {
+		return errors.New("email is already verified")
+	}
+
+	return s.sendVerificationEmail(ctx, user)
+}
+
+// ForgotPassword отправляет ссылку для сброса пароля. Ответ не зависит от того,
+// существует ли аккаунт, чтобы по нему нельзя было перебирать адреса.
+func (s *AuthService) ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil || user.IsBlocked {
+		return nil
+	}
+
+	rawToken, err := s.createToken(ctx, user.ID, user.Email, entities.TokenPurposePasswordReset, s.settings.PasswordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.link("/reset-password", rawToken)
+	return s.mailer.Send(ctx, appInterfaces.MailMessage{
+		To:      user.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка действует %s. Если вы не запрашивали сброс, просто проигнорируйте письмо.\n",
+			user.Username, link, s.settings.PasswordResetTokenTTL,
+		),
+	})
+}
+
+// ResetPassword задает новый пароль по токену и завершает все сессии пользователя
+func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) error {
+	token, err := s.consumeToken(ctx, req.Token, entities.TokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	// Письмо со ссылкой пришло на этот адрес, значит почта подтверждена
+	if strings.EqualFold(user.Email, token.Email) {
+		user.EmailVerified = true
+	}
+
+	if err := s.setPassword(ctx, user, req.Password); err != nil {
+		return err
+	}
+
+	s.verificationRepo.InvalidateForUser(ctx, user.ID, entities.TokenPurposePasswordReset)
+	return nil
+}
+
+// ChangePassword меняет пароль после проверки текущего и выдает новую пару токенов;
+// остальные сессии пользователя завершаются
+func (s *AuthService) ChangePassword(ctx context.Context, userID uint, req dto.ChangePasswordRequest) (*dto.AuthResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !s.passwordUtil.CheckPasswordHash(req.CurrentPassword, user.PasswordHash) {
+		return nil, errors.New("invalid current password")
+	}
+
+	if err := s.setPassword(ctx, user, req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	// Перечитываем пользователя, чтобы новый токен получил актуальную версию
+	user, err = s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, user, "")
+}
+
+// requestEmailChange отправляет ссылку на новый адрес; до перехода по ней
+// аккаунт остается на прежней почте
+func (s *AuthService) requestEmailChange(ctx context.Context, user *entities.User, newEmail string) error {
+	if _, err := s.userRepo.FindByEmail(ctx, newEmail); err == nil {
+		return errors.New("email already in use")
+	}
+
+	if err := s.verificationRepo.InvalidateForUser(ctx, user.ID, entities.TokenPurposeEmailChange); err != nil {
+		return err
+	}
+
+	rawToken, err := s.createToken(ctx, user.ID, newEmail, entities.TokenPurposeEmailChange, s.settings.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.link("/confirm-email", rawToken)
+	return s.mailer.Send(ctx, appInterfaces.MailMessage{
+		To:      newEmail,
+		Subject: "Смена адреса электронной почты",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы привязать этот адрес к аккаунту, перейдите по ссылке:\n%s\n\nСсылка действует %s. Если вы не меняли почту, просто проигнорируйте письмо.\n",
+			user.Username, link, s.settings.EmailVerificationTTL,
+		),
+	})
+}
+
+// cancelEmailChange отзывает ссылки смены адреса, выписанные пользователю
+func (s *AuthService) cancelEmailChange(ctx context.Context, userID uint) error {
+	return s.verificationRepo.InvalidateForUser(ctx, userID, entities.TokenPurposeEmailChange)
+}
+
+// ConfirmEmailChange переводит аккаунт на новый адрес и сообщает об этом на прежний
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, req dto.VerifyEmailRequest) error {
+	token, err := s.consumeToken(ctx, req.Token, entities.TokenPurposeEmailChange)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	// Адрес могли занять, пока письмо шло
+	if existing, err := s.userRepo.FindByEmail(ctx, token.Email); err == nil && existing.ID != user.ID {
+		return errors.New("email already in use")
+	}
+
+	oldEmail := user.Email
+	user.Email = token.Email
+	user.EmailVerified = true
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateColumns(ctx, user, "email", "email_verified", "updated_at"); err != nil {
+		return err
+	}
+
+	// Ссылки, выписанные на старый адрес, больше не действуют
+	s.verificationRepo.InvalidateForUser(ctx, user.ID, entities.TokenPurposeEmailVerification)
+	s.verificationRepo.InvalidateForUser(ctx, user.ID, entities.TokenPurposePasswordReset)
+
+	if err := s.mailer.Send(ctx, appInterfaces.MailMessage{
+		To:      oldEmail,
+		Subject: "Адрес электронной почты изменен",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nАдрес электронной почты вашего аккаунта изменен на %s. Если это были не вы, восстановите доступ через службу поддержки.\n",
+			user.Username, user.Email,
+		),
+	}); err != nil {
+		log.Printf("failed to notify %s about email change: %v", oldEmail, err)
+	}
+	return nil
+}
+
+// setPassword сохраняет новый пароль и отзывает все выданные токены
+func (s *AuthService) setPassword(ctx context.Context, user *entities.User, password string) error {
+	hashedPassword, err := s.passwordUtil.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash = hashedPassword
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateColumns(ctx, user, "password_hash", "updated_at"); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.IncrementTokenVersion(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	s.accessChecker.Invalidate(user.ID)
+	return nil
+}
+
+// resetCredentials убирает пароль и второй фактор, отзывает токены и неиспользованные ссылки из писем.
+// Подтверждение почты сохраняется вместе с паролем: его выставляет вызывающий
+func (s *AuthService) resetCredentials(ctx context.Context, user *entities.User) error {
+	user.PasswordHash = ""
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateColumns(ctx, user, "password_hash", "email_verified", "updated_at"); err != nil {
+		return err
+	}
+
+	// Второй фактор сбрасываем без оглядки на копию: его могли включить после ее чтения
+	if err := s.userRepo.UpdateTwoFactor(ctx, user.ID, "", false); err != nil {
+		return err
+	}
+	user.TOTPEnabled = false
+	user.TOTPSecret = ""
+	if err := s.twoFactor.recoveryRepo.DeleteForUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	for _, purpose := range []string{
+		entities.TokenPurposeEmailVerification,
+		entities.TokenPurposePasswordReset,
+		entities.TokenPurposeEmailChange,
+	} {
+		if err := s.verificationRepo.InvalidateForUser(ctx, user.ID, purpose); err != nil {
+			return err
+		}
+	}
+
+	if err := s.userRepo.IncrementTokenVersion(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.refreshTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		return err
+	}
+	s.accessChecker.Invalidate(user.ID)
+	return nil
+}
+
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *entities.User) error {
+	if err := s.verificationRepo.InvalidateForUser(ctx, user.ID, entities.TokenPurposeEmailVerification); err != nil {
+		return err
+	}
+
+	rawToken, err := s.createToken(ctx, user.ID, user.Email, entities.TokenPurposeEmailVerification, s.settings.EmailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := s.link("/verify-email", rawToken)
+	return s.mailer.Send(ctx, appInterfaces.MailMessage{
+		To:      user.Email,
+		Subject: "Подтверждение адреса электронной почты",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nПодтвердите адрес электронной почты, перейдя по ссылке:\n%s\n\nСсылка действует %s.\n",
+			user.Username, link, s.settings.EmailVerificationTTL,
+		),
+	})
+}
+
+// createToken сохраняет хеш одноразового токена и возвращает сам токен для письма
+func (s *AuthService) createToken(ctx context.Context, userID uint, email, purpose string, ttl time.Duration) (string, error) {
+	rawToken, tokenHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	token := &entities.VerificationToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		Purpose:   purpose,
+		Email:     email,
+		ExpiresAt: time.Now().Add(ttl),
+		CreatedAt: time.Now(),
+	}
+	if err := s.verificationRepo.Create(ctx, token); err != nil {
+		return "", err
+	}
+	return rawToken, nil
+}
+
+// consumeToken проверяет токен и атомарно гасит его, чтобы ссылку нельзя было использовать дважды
+func (s *AuthService) consumeToken(ctx context.Context, rawToken, purpose string) (*entities.VerificationToken, error) {
+	token, err := s.verificationRepo.FindByHash(ctx, utils.HashToken(rawToken), purpose)
+	if err != nil || token.IsUsed() || token.IsExpired() {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	used, err := s.verificationRepo.MarkUsed(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errors.New("invalid or expired token")
+	}
+	return token, nil
+}
+
+func (s *AuthService) link(path, token string) string {
+	return strings.TrimRight(s.settings.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
 }
 
 func (s *AuthService) GetProfile(ctx context.Context, userID uint) (*dto.UserResponse, error) {
@@ -128,18 +638,48 @@ func (s *AuthService) GetProfile(ctx context.Context, userID uint) (*dto.UserRes
 		return nil, err
 	}
 
-	return &dto.UserResponse{
-		ID:         user.ID,
-		Username:   user.Username,
-		Email:      user.Email,
-		Role:       user.Role,
-		AvatarURL:  user.AvatarURL,
-		IsBlocked:  user.IsBlocked,
-		LastOnline: user.LastOnline.Format(time.RFC3339),
-		CreatedAt:  user.CreatedAt.Format(time.RFC3339),
-	}, nil
+	response := userToDTO(user)
+	return &response, nil
 }
 
 func (s *AuthService) UpdateLastOnline(ctx context.Context, userID uint) error {
 	return s.userRepo.UpdateLastOnline(ctx, userID)
 }
+
+func userToDTO(user *entities.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		Role:              user.Role,
+		AvatarURL:         user.AvatarURL,
+		Bio:               user.Bio,
+		IsBlocked:         user.IsBlocked,
+		HideParticipation: user.HideParticipation,
+		LockedUntil:       formatOptionalTime(user.LockedUntil),
+		TOTPEnabled:       user.TOTPEnabled,
+		EmailVerified:     user.EmailVerified,
+		LastOnline:        user.LastOnline.Format(time.RFC3339),
+		CreatedAt:         user.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// userToShort публичные сведения об авторе комментария или мероприятия
+func userToShort(user *entities.User) dto.UserShort {
+	return dto.UserShort{
+		ID:                  user.ID,
+		Username:            user.Username,
+		Email:               user.Email,
+		Role:                user.Role,
+		AvatarURL:           user.AvatarURL,
+		Bio:                 user.Bio,
+		IsVerifiedOrganizer: user.IsVerifiedOrganizer(),
+	}
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil || time.Now().After(*t) {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"auth-system/internal/domain/entities"
+)
+
+// Режимы сортировки комментариев
+const (
+	CommentSortBest          = "best"
+	CommentSortTop           = "top"
+	CommentSortNew           = "new"
+	CommentSortControversial = "controversial"
+)
+
+// wilsonZ квантиль нормального распределения для доверительного уровня 95%
+const wilsonZ = 1.96
+
+// rankComments сортирует комментарии одного уровня, при равенстве более новые выше
+func rankComments(comments []*entities.Comment, mode string) {
+	var rank func(c *entities.Comment) float64
+	switch mode {
+	case CommentSortNew:
+		rank = nil
+	case CommentSortTop:
+		rank = func(c *entities.Comment) float64 { return float64(c.Upvotes - c.Downvotes) }
+	case CommentSortControversial:
+		rank = func(c *entities.Comment) float64 { return controversy(c.Upvotes, c.Downvotes) }
+	default:
+		rank = func(c *entities.Comment) float64 { return wilsonLowerBound(c.Upvotes, c.Downvotes) }
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		if rank != nil {
+			ri, rj := rank(comments[i]), rank(comments[j])
+			if ri != rj {
+				return ri > rj
+			}
+		}
+		return comments[i].CreatedAt.After(comments[j].CreatedAt)
+	})
+}
+
+// wilsonLowerBound нижняя граница доверительного интервала Уилсона для доли положительных голосов
+func wilsonLowerBound(upvotes, downvotes int) float64 {
+	n := float64(upvotes + downvotes)
+	if n == 0 {
+		return 0
+	}
+
+	p := float64(upvotes) / n
+	z2 := wilsonZ * wilsonZ
+	return (p + z2/(2*n) - wilsonZ*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
+
+// controversy растет с числом голосов и тем сильнее, чем ближе баланс "за" и "против"
+func controversy(upvotes, downvotes int) float64 {
+	if upvotes <= 0 || downvotes <= 0 {
+		return 0
+	}
+
+	magnitude := float64(upvotes + downvotes)
+	balance := float64(downvotes) / float64(upvotes)
+	if upvotes < downvotes {
+		balance = float64(upvotes) / float64(downvotes)
+	}
+	return math.Pow(magnitude, balance)
+}
@@ -104,19 +104,33 @@ func (s *CommentService) CreateComment(ctx context.Context, req dto.CreateCommen
 	return s.commentToDTO(comment), nil
 }
 
-func (s *CommentService) GetComments(ctx context.Context, eventID uint) ([]dto.CommentResponse, error) {
-	comments, err := s.commentRepo.FindByEventID(ctx, eventID)
+// defaultCommentDepth глубина дерева, отдаваемая без явного параметра depth
+const defaultCommentDepth = 10
+
+func (s *CommentService) GetComments(ctx context.Context, eventID uint, query dto.CommentTreeQuery) ([]dto.CommentResponse, error) {
+	maxDepth := commentTreeDepth(query)
+
+	comments, err := s.commentRepo.FindByEventID(ctx, eventID, maxDepth)
 	if err != nil {
 		return nil, err
 	}
 
-	// Преобразуем в DTO
-	response := make([]dto.CommentResponse, len(comments))
-	for i, comment := range comments {
-		response[i] = *s.commentToDTO(&comment)
+	return s.buildCommentTree(comments, maxDepth, query.Sort), nil
+}
+
+// GetThread возвращает ветку комментария для раскрытия отметки "продолжить ветку"
+func (s *CommentService) GetThread(ctx context.Context, commentID uint, query dto.CommentTreeQuery) ([]dto.CommentResponse, error) {
+	maxDepth := commentTreeDepth(query)
+
+	comments, err := s.commentRepo.FindThread(ctx, commentID, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, entities.ErrCommentNotFound
 	}
 
-	return response, nil
+	return s.buildCommentTree(comments, maxDepth, query.Sort), nil
 }
 
 func (s *CommentService) UpdateComment(ctx context.Context, commentID uint, req dto.UpdateCommentRequest, userID uint) (*dto.CommentResponse, error) {
@@ -157,7 +171,11 @@ func (s *CommentService) DeleteComment(ctx context.Context, commentID, userID ui
 	}
 
 	if comment.UserID != userID {
-		return errors.New("not authorized to delete this comment")
+		// Чужие комментарии удаляют только модераторы
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil || !user.Can(entities.PermCommentsDelete) {
+			return errors.New("not authorized to delete this comment")
+		}
 	}
 
 	// Мягкое удаление
@@ -202,12 +220,14 @@ func (s *CommentService) VoteComment(ctx context.Context, commentID, userID uint
 		s.commentRepo.CreateVote(ctx, vote)
 	}
 
-	// Получаем обновленный счет
-	score, err := s.commentRepo.GetScore(ctx, commentID)
+	// Пересчитываем голоса и сохраняем их в комментарии
+	upvotes, downvotes, err := s.commentRepo.RecountVotes(ctx, commentID)
 	if err != nil {
 		return nil, err
 	}
-	comment.Score = score
+	comment.Upvotes = upvotes
+	comment.Downvotes = downvotes
+	comment.Score = upvotes - downvotes
 
 	// Загружаем пользователя
 	user, err := s.userRepo.FindByID(ctx, comment.UserID)
@@ -219,29 +239,129 @@ func (s *CommentService) VoteComment(ctx context.Context, commentID, userID uint
 	return s.commentToDTO(comment), nil
 }
 
+func commentTreeDepth(query dto.CommentTreeQuery) int {
+	if query.MaxDepth > 0 {
+		return query.MaxDepth
+	}
+	return defaultCommentDepth
+}
+
+// buildCommentTree собирает вложенные ответы из плоского списка, упорядоченного по глубине
+func (s *CommentService) buildCommentTree(comments []entities.Comment, maxDepth int, sortMode string) []dto.CommentResponse {
+	loaded := make(map[uint]bool, len(comments))
+	for _, comment := range comments {
+		loaded[comment.ID] = true
+	}
+
+	children := make(map[uint][]*entities.Comment)
+	var roots []*entities.Comment
+	for i := range comments {
+		comment := &comments[i]
+		if comment.ParentID != nil && loaded[*comment.ParentID] {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+		} else {
+			roots = append(roots, comment)
+		}
+	}
+
+	return s.assembleReplies(roots, children, maxDepth, sortMode)
+}
+
+func (s *CommentService) assembleReplies(nodes []*entities.Comment, children map[uint][]*entities.Comment, maxDepth int, sortMode string) []dto.CommentResponse {
+	// Ранжирование применяется отдельно на каждом уровне дерева
+	rankComments(nodes, sortMode)
+
+	result := make([]dto.CommentResponse, 0, len(nodes))
+	for _, node := range nodes {
+		item := s.commentToDTO(node)
+		item.Replies = s.assembleReplies(children[node.ID], children, maxDepth, sortMode)
+		item.HasMoreReplies = node.Depth >= maxDepth && node.ReplyCount > 0
+
+		// Удаленный комментарий оставляем только как заглушку над живыми ответами
+		if node.IsDeleted && len(item.Replies) == 0 && !item.HasMoreReplies {
+			continue
+		}
+		result = append(result, *item)
+	}
+	return result
+}
+
+// GetUserComments комментарии пользователя для личного кабинета, новые сверху
+func (s *CommentService) GetUserComments(ctx context.Context, userID uint, query dto.UserCommentsQuery) (*dto.UserCommentsResponse, error) {
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	filter := make(map[string]interface{})
+	if query.EventID != 0 {
+		filter["event_id"] = query.EventID
+	}
+	if query.IncludeReplies {
+		filter["include_replies"] = true
+	}
+
+	comments, total, err := s.commentRepo.FindByUser(ctx, userID, filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.UserCommentsResponse{
+		Comments: make([]dto.UserCommentResponse, len(comments)),
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}
+	for i, comment := range comments {
+		response.Comments[i] = dto.UserCommentResponse{
+			ID:         comment.ID,
+			Content:    comment.Content,
+			ParentID:   comment.ParentID,
+			EventID:    comment.EventID,
+			EventTitle: comment.EventTitle,
+			EventURL:   fmt.Sprintf("/events/%d", comment.EventID),
+			Score:      comment.Score,
+			Upvotes:    comment.Upvotes,
+			Downvotes:  comment.Downvotes,
+			ReplyCount: comment.ReplyCount,
+			CreatedAt:  comment.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:  comment.UpdatedAt.Format(time.RFC3339),
+			User:       userToShort(&comment.User),
+			IsReply:    comment.UserID != userID,
+		}
+	}
+	return response, nil
+}
+
 func (s *CommentService) commentToDTO(comment *entities.Comment) *dto.CommentResponse {
-	// Преобразуем ответы (реплики)
-	// replies := make([]dto.CommentResponse, len(comment.Replies))
-	// for i, reply := range comment.Replies {
-	// 	replies[i] = *s.commentToDTO(&reply)
-	// }
-
-	return &dto.CommentResponse{
-		ID:        comment.ID,
-		Content:   comment.Content,
-		EventID:   comment.EventID,
-		UserID:    comment.UserID,
-		ParentID:  comment.ParentID,
-		Score:     comment.Score,
-		IsDeleted: comment.IsDeleted,
-		CreatedAt: comment.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: comment.UpdatedAt.Format(time.RFC3339),
-		User: dto.UserShort{
-			ID:       comment.User.ID,
-			Username: comment.User.Username,
-			Email:    comment.User.Email,
-			Role:     comment.User.Role,
-		},
-		//Replies: replies,
+	response := &dto.CommentResponse{
+		ID:         comment.ID,
+		Content:    comment.Content,
+		EventID:    comment.EventID,
+		UserID:     comment.UserID,
+		ParentID:   comment.ParentID,
+		Score:      comment.Score,
+		Upvotes:    comment.Upvotes,
+		Downvotes:  comment.Downvotes,
+		IsDeleted:  comment.IsDeleted,
+		CreatedAt:  comment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  comment.UpdatedAt.Format(time.RFC3339),
+		Depth:      comment.Depth,
+		ReplyCount: comment.ReplyCount,
+		User:       userToShort(&comment.User),
+		Replies:    []dto.CommentResponse{},
+	}
+
+	// Не раскрываем содержимое и автора удаленного комментария
+	if comment.IsDeleted {
+		response.Content = ""
+		response.UserID = 0
+		response.User = dto.UserShort{}
 	}
+
+	return response
 }
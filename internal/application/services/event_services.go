@@ -1,14 +1,18 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"math"
 	"time"
 
 	"auth-system/internal/application/dto"
 	appInterfaces "auth-system/internal/application/interfaces"
 	"auth-system/internal/domain/entities"
+	"auth-system/internal/pkg/export"
 
 	"github.com/gosimple/slug"
 )
@@ -28,6 +32,18 @@ func NewEventService(eventRepo appInterfaces.EventRepository, userRepo appInterf
 }
 
 func (s *EventService) CreateEvent(ctx context.Context, req dto.CreateEventRequest, userID uint) (*dto.EventResponse, error) {
+	creator, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !creator.EmailVerified {
+		return nil, errors.New("email is not verified")
+	}
+
+	// Мероприятия проверенных организаторов публикуются без очереди модерации
+	autoVerified := creator.PublishesWithoutReview()
+
 	event := &entities.Event{
 		Title:           req.Title,
 		Description:     req.Description,
@@ -39,7 +55,7 @@ func (s *EventService) CreateEvent(ctx context.Context, req dto.CreateEventReque
 		Price:           req.Price,
 		Address:         req.Address,
 		CreatorID:       userID,
-		IsVerified:      false,
+		IsVerified:      autoVerified,
 		IsActive:        true,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
@@ -60,12 +76,17 @@ func (s *EventService) CreateEvent(ctx context.Context, req dto.CreateEventReque
 		return nil, err
 	}
 
-	// Notify admins
-	admins, err := s.userRepo.GetAdmins(ctx)
-	if err == nil && len(admins) > 0 {
-		for _, admin := range admins {
+	event.Creator = *creator
+
+	// Notify everyone who handles the verification queue
+	if autoVerified {
+		return s.eventToDTO(event), nil
+	}
+	moderators, err := s.userRepo.GetByRoles(ctx, entities.RolesWithPermission(entities.PermEventsVerify))
+	if err == nil && len(moderators) > 0 {
+		for _, moderator := range moderators {
 			notification := &entities.Notification{
-				UserID:    admin.ID,
+				UserID:    moderator.ID,
 				Message:   fmt.Sprintf("Новое мероприятие создано: %s", event.Title),
 				Type:      "event_created",
 				Read:      false,
@@ -88,6 +109,23 @@ func (s *EventService) GetEvents(ctx context.Context, filter dto.EventFilter) ([
 	if !filter.Date.IsZero() {
 		filterMap["date"] = filter.Date
 	}
+	if filter.BBox != nil {
+		filterMap["bbox"] = entities.BoundingBox{
+			MinLon: filter.BBox.MinLon,
+			MinLat: filter.BBox.MinLat,
+			MaxLon: filter.BBox.MaxLon,
+			MaxLat: filter.BBox.MaxLat,
+		}
+	}
+	if filter.Lat != nil && filter.Lon != nil {
+		filterMap["center"] = entities.GeoPoint{Latitude: *filter.Lat, Longitude: *filter.Lon}
+		if filter.RadiusKm > 0 {
+			filterMap["radius_m"] = filter.RadiusKm * 1000
+		}
+	}
+	if filter.SortBy != "" {
+		filterMap["sort"] = filter.SortBy
+	}
 
 	events, err := s.eventRepo.FindAll(ctx, filterMap)
 	if err != nil {
@@ -125,6 +163,10 @@ func (s *EventService) UpdateEvent(ctx context.Context, id uint, req dto.UpdateE
 		return nil, errors.New("not authorized to update this event")
 	}
 
+	if event.IsCancelled() {
+		return nil, entities.ErrEventCancelled
+	}
+
 	// Update fields
 	if req.Title != "" {
 		event.Title = req.Title
@@ -150,6 +192,13 @@ func (s *EventService) UpdateEvent(ctx context.Context, id uint, req dto.UpdateE
 		return nil, err
 	}
 
+	// После увеличения лимита места достаются очереди
+	if req.MaxParticipants != nil {
+		s.promoteWaitlisted(ctx, event.ID)
+	}
+
+	s.notifyInterested(ctx, event)
+
 	return s.eventToDTO(event), nil
 }
 
@@ -163,45 +212,72 @@ func (s *EventService) DeleteEvent(ctx context.Context, id uint, userID uint) er
 		return errors.New("not authorized to delete this event")
 	}
 
-	event.IsActive = false
-	event.UpdatedAt = time.Now()
+	return s.eventRepo.Deactivate(ctx, id)
+}
+
+// CancelEvent отменяет мероприятие. В отличие от удаления оно остается видимым
+// с пометкой об отмене, а все записавшиеся получают уведомление с причиной
+func (s *EventService) CancelEvent(ctx context.Context, eventID, userID uint, req dto.CancelEventRequest) (*dto.EventResponse, error) {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	// Удаленное мероприятие отменить уже нельзя
+	if err != nil || !event.IsActive {
+		return nil, entities.ErrEventNotFound
+	}
 
-	return s.eventRepo.Update(ctx, event)
+	if !event.CanEdit(userID) {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil || !user.Can(entities.PermEventsDelete) {
+			return nil, fmt.Errorf("%w to cancel this event", entities.ErrNotAuthorized)
+		}
+	}
+
+	if err := s.eventRepo.CancelEvent(ctx, eventID, req.Reason); err != nil {
+		return nil, err
+	}
+
+	event, err = s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyCancelled(ctx, event, userID)
+
+	return s.eventToDTO(event), nil
 }
 
-func (s *EventService) Participate(ctx context.Context, eventID, userID uint) error {
+// Participate устанавливает статус участия (по умолчанию going). Если мест нет,
+// going превращается в место в очереди (или entities.ErrEventFull, если очередь не нужна)
+func (s *EventService) Participate(ctx context.Context, eventID, userID uint, query dto.ParticipateQuery) (*dto.ParticipationResponse, error) {
 	event, err := s.eventRepo.FindByID(ctx, eventID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !event.IsActive {
-		return errors.New("event is not active")
+		return nil, errors.New("event is not active")
 	}
 
-	// Check if event is full
-	if event.MaxParticipants != nil {
-		count, err := s.eventRepo.GetParticipantCount(ctx, eventID)
-		if err != nil {
-			return err
-		}
-		if int(count) >= *event.MaxParticipants {
-			return errors.New("event is full")
-		}
+	if event.IsCancelled() {
+		return nil, entities.ErrEventCancelled
 	}
 
-	// Check if already participating
-	isParticipant, err := s.eventRepo.IsParticipant(ctx, eventID, userID)
-	if err != nil {
-		return err
+	status := query.Status
+	if status == "" {
+		status = entities.ParticipantStatusGoing
 	}
-	if isParticipant {
-		return errors.New("already participating")
+
+	allowWaitlist := query.Waitlist == nil || *query.Waitlist
+	status, promoted, err := s.eventRepo.JoinEvent(ctx, eventID, userID, status, allowWaitlist)
+	if err != nil {
+		return nil, err
 	}
+	s.notifyPromoted(ctx, eventID, promoted)
 
-	// Add participant
-	if err := s.eventRepo.AddParticipant(ctx, eventID, userID); err != nil {
-		return err
+	if status == entities.ParticipantStatusWaitlisted {
+		return s.GetParticipation(ctx, eventID, userID)
+	}
+	if status != entities.ParticipantStatusGoing {
+		return &dto.ParticipationResponse{EventID: eventID, Status: status}, nil
 	}
 
 	// Notify event creator
@@ -216,11 +292,242 @@ func (s *EventService) Participate(ctx context.Context, eventID, userID uint) er
 		s.notificationRepo.Create(ctx, notification)
 	}
 
-	return nil
+	return &dto.ParticipationResponse{EventID: eventID, Status: status}, nil
+}
+
+// GetParticipation статус пользователя и его место в очереди
+func (s *EventService) GetParticipation(ctx context.Context, eventID, userID uint) (*dto.ParticipationResponse, error) {
+	participant, err := s.eventRepo.FindParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, errors.New("not participating")
+	}
+
+	response := &dto.ParticipationResponse{
+		EventID:        eventID,
+		Status:         participant.Status,
+		RemindersMuted: participant.RemindersMuted,
+	}
+	if participant.IsWaitlisted() {
+		position, err := s.eventRepo.GetWaitlistPosition(ctx, eventID, userID)
+		if err != nil {
+			return nil, err
+		}
+		response.WaitlistPosition = position
+	}
+	return response, nil
 }
 
+// SetReminders отключает или возвращает напоминания о мероприятии для участника
+func (s *EventService) SetReminders(ctx context.Context, eventID, userID uint, req dto.RemindersRequest) (*dto.ParticipationResponse, error) {
+	if err := s.eventRepo.SetRemindersMuted(ctx, eventID, userID, *req.Muted); err != nil {
+		return nil, err
+	}
+	return s.GetParticipation(ctx, eventID, userID)
+}
+
+// CancelParticipation освобождает место и отдает его первому в очереди
 func (s *EventService) CancelParticipation(ctx context.Context, eventID, userID uint) error {
-	return s.eventRepo.RemoveParticipant(ctx, eventID, userID)
+	promoted, err := s.eventRepo.LeaveEvent(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+
+	s.notifyPromoted(ctx, eventID, promoted)
+	return nil
+}
+
+// promoteWaitlisted занимает освободившиеся места участниками из очереди
+func (s *EventService) promoteWaitlisted(ctx context.Context, eventID uint) {
+	promoted, err := s.eventRepo.PromoteWaitlisted(ctx, eventID)
+	if err != nil {
+		log.Printf("failed to promote waitlist of event %d: %v", eventID, err)
+		return
+	}
+	s.notifyPromoted(ctx, eventID, promoted)
+}
+
+// notifyInterested сообщает об изменениях тем, кто отметил мероприятие, но еще не записался
+func (s *EventService) notifyInterested(ctx context.Context, event *entities.Event) {
+	userIDs, err := s.eventRepo.GetParticipantIDs(ctx, event.ID, []string{
+		entities.ParticipantStatusInterested,
+		entities.ParticipantStatusMaybe,
+	})
+	if err != nil {
+		log.Printf("failed to load interested users of event %d: %v", event.ID, err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		if userID == event.CreatorID {
+			continue
+		}
+		notification := &entities.Notification{
+			UserID:    userID,
+			Message:   fmt.Sprintf("Мероприятие изменено: %s", event.Title),
+			Type:      "event_updated",
+			Read:      false,
+			CreatedAt: time.Now(),
+		}
+		s.notificationRepo.Create(ctx, notification)
+	}
+}
+
+func (s *EventService) notifyCancelled(ctx context.Context, event *entities.Event, actorID uint) {
+	userIDs, err := s.eventRepo.GetParticipantIDs(ctx, event.ID, []string{
+		entities.ParticipantStatusGoing,
+		entities.ParticipantStatusWaitlisted,
+		entities.ParticipantStatusInterested,
+		entities.ParticipantStatusMaybe,
+	})
+	if err != nil {
+		log.Printf("failed to load participants of event %d: %v", event.ID, err)
+	}
+	// Если отменил модератор, создатель тоже должен об этом узнать
+	userIDs = append(userIDs, event.CreatorID)
+
+	notified := make(map[uint]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == actorID || notified[userID] {
+			continue
+		}
+		notified[userID] = true
+		notification := &entities.Notification{
+			UserID:    userID,
+			Message:   fmt.Sprintf("Мероприятие отменено: %s. Причина: %s", event.Title, event.CancelReason),
+			Type:      "event_cancelled",
+			Read:      false,
+			CreatedAt: time.Now(),
+		}
+		s.notificationRepo.Create(ctx, notification)
+	}
+}
+
+func (s *EventService) notifyPromoted(ctx context.Context, eventID uint, promoted []uint) {
+	if len(promoted) == 0 {
+		return
+	}
+
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return
+	}
+	for _, userID := range promoted {
+		notification := &entities.Notification{
+			UserID:    userID,
+			Message:   fmt.Sprintf("Освободилось место: вы записаны на мероприятие %s", event.Title),
+			Type:      "waitlist_promoted",
+			Read:      false,
+			CreatedAt: time.Now(),
+		}
+		s.notificationRepo.Create(ctx, notification)
+	}
+}
+
+// hiddenParticipantName подставляется вместо имени участника, скрывшего участие
+const hiddenParticipantName = "Скрытый участник"
+
+// GetEventParticipants список участников для создателя мероприятия и администрации
+func (s *EventService) GetEventParticipants(ctx context.Context, eventID, userID uint, query dto.ParticipantsQuery) (*dto.EventParticipantsResponse, error) {
+	showHidden, err := s.authorizeParticipantsView(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = 50
+	}
+
+	participants, total, err := s.eventRepo.GetEventParticipants(ctx, eventID, query.Status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.EventParticipantsResponse{
+		Participants: make([]dto.EventParticipantResponse, len(participants)),
+		Total:        total,
+		Page:         page,
+		Limit:        limit,
+	}
+	for i, participant := range participants {
+		response.Participants[i] = participantToDTO(&participant, showHidden)
+	}
+	return response, nil
+}
+
+// ExportParticipants выгружает всех участников (с учетом фильтра) в CSV или XLSX
+func (s *EventService) ExportParticipants(ctx context.Context, eventID, userID uint, query dto.ParticipantsExportQuery) (*dto.ExportFile, error) {
+	showHidden, err := s.authorizeParticipantsView(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants, _, err := s.eventRepo.GetEventParticipants(ctx, eventID, query.Status, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	header := []string{"Имя пользователя", "Дата записи", "Статус"}
+	rows := make([][]string, len(participants))
+	for i, participant := range participants {
+		item := participantToDTO(&participant, showHidden)
+		rows[i] = []string{item.User.Username, item.JoinedAt, item.Status}
+	}
+
+	var buf bytes.Buffer
+	file := &dto.ExportFile{FileName: fmt.Sprintf("event-%d-participants", eventID)}
+	if query.Format == "xlsx" {
+		err = export.WriteXLSX(&buf, "Участники", header, rows)
+		file.FileName += ".xlsx"
+		file.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	} else {
+		err = export.WriteCSV(&buf, header, rows)
+		file.FileName += ".csv"
+		file.ContentType = "text/csv; charset=utf-8"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file.Content = buf.Bytes()
+	return file, nil
+}
+
+// authorizeParticipantsView пускает создателя и тех, кто видит все мероприятия.
+// Скрытых участников раскрывает только право просмотра пользователей.
+func (s *EventService) authorizeParticipantsView(ctx context.Context, eventID, userID uint) (bool, error) {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return false, entities.ErrEventNotFound
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil || (!event.CanEdit(userID) && !user.Can(entities.PermEventsViewAll)) {
+		return false, fmt.Errorf("%w to view participants of this event", entities.ErrNotAuthorized)
+	}
+	return user.Can(entities.PermUsersView), nil
+}
+
+func participantToDTO(participant *entities.EventParticipant, showHidden bool) dto.EventParticipantResponse {
+	response := dto.EventParticipantResponse{
+		EventID:  participant.EventID,
+		UserID:   participant.UserID,
+		Status:   participant.Status,
+		JoinedAt: participant.JoinedAt.Format(time.RFC3339),
+		User:     userToShort(&participant.User),
+	}
+	response.User.Email = ""
+
+	if participant.User.HideParticipation && !showHidden {
+		response.UserID = 0
+		response.User = dto.UserShort{Username: hiddenParticipantName}
+		response.Hidden = true
+	}
+	return response
 }
 
 func (s *EventService) GetUserEvents(ctx context.Context, userID uint) ([]dto.EventResponse, error) {
@@ -237,8 +544,13 @@ func (s *EventService) GetUserEvents(ctx context.Context, userID uint) ([]dto.Ev
 	return response, nil
 }
 
-func (s *EventService) GetParticipatedEvents(ctx context.Context, userID uint) ([]dto.EventResponse, error) {
-	events, err := s.eventRepo.GetParticipatedEvents(ctx, userID)
+func (s *EventService) GetParticipatedEvents(ctx context.Context, userID uint, query dto.ParticipatedEventsQuery) ([]dto.EventResponse, error) {
+	status := query.Status
+	if status == "" {
+		status = entities.ParticipantStatusGoing
+	}
+
+	events, err := s.eventRepo.GetParticipatedEvents(ctx, userID, status)
 	if err != nil {
 		return nil, err
 	}
@@ -255,6 +567,87 @@ func (s *EventService) FilterEvents(ctx context.Context, filter dto.EventFilter)
 	return s.GetEvents(ctx, filter)
 }
 
+// Параметры "пагинации по масштабу"
+const (
+	// clusterRadiusPx размер ячейки кластера в пикселях экрана
+	clusterRadiusPx = 60
+	// maxClusterZoom масштаб, начиная с которого метки больше не объединяются
+	maxClusterZoom = 17
+)
+
+func (s *EventService) GetClusters(ctx context.Context, req dto.ClusterRequest) (*dto.ClusterResponse, error) {
+	filterMap := make(map[string]interface{})
+	filterMap["bbox"] = entities.BoundingBox{
+		MinLon: req.BBox.MinLon,
+		MinLat: req.BBox.MinLat,
+		MaxLon: req.BBox.MaxLon,
+		MaxLat: req.BBox.MaxLat,
+	}
+	if len(req.Type) > 0 {
+		filterMap["type"] = req.Type
+	}
+
+	response := &dto.ClusterResponse{
+		Zoom:     req.Zoom,
+		Clusters: []dto.EventCluster{},
+		Events:   []dto.EventResponse{},
+	}
+
+	// При максимальном приближении отдаем все метки без объединения
+	if req.Zoom >= maxClusterZoom {
+		events, err := s.eventRepo.FindAll(ctx, filterMap)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			response.Events = append(response.Events, *s.eventToDTO(&event))
+		}
+		return response, nil
+	}
+
+	clusters, err := s.eventRepo.GetClusters(ctx, filterMap, clusterGridSize(req.Zoom))
+	if err != nil {
+		return nil, err
+	}
+
+	// Одиночные точки возвращаем как обычные мероприятия
+	var singleIDs []uint
+	for _, cluster := range clusters {
+		if cluster.IsSingle() {
+			singleIDs = append(singleIDs, cluster.EventID)
+			continue
+		}
+		response.Clusters = append(response.Clusters, dto.EventCluster{
+			Count:     cluster.Count,
+			Latitude:  cluster.Latitude,
+			Longitude: cluster.Longitude,
+			Bounds: dto.BoundingBox{
+				MinLon: cluster.MinLon,
+				MinLat: cluster.MinLat,
+				MaxLon: cluster.MaxLon,
+				MaxLat: cluster.MaxLat,
+			},
+		})
+	}
+
+	if len(singleIDs) > 0 {
+		events, err := s.eventRepo.FindAll(ctx, map[string]interface{}{"ids": singleIDs})
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			response.Events = append(response.Events, *s.eventToDTO(&event))
+		}
+	}
+
+	return response, nil
+}
+
+// clusterGridSize переводит размер ячейки в пикселях в градусы для тайлов 256px
+func clusterGridSize(zoom int) float64 {
+	return 360.0 / (256.0 * math.Pow(2, float64(zoom))) * clusterRadiusPx
+}
+
 func (s *EventService) eventToDTO(event *entities.Event) *dto.EventResponse {
 	// Convert tags
 	tags := make([]dto.Tag, len(event.Tags))
@@ -269,35 +662,30 @@ func (s *EventService) eventToDTO(event *entities.Event) *dto.EventResponse {
 	// Convert media
 	media := make([]dto.Media, len(event.Media))
 	for i, m := range event.Media {
-		media[i] = dto.Media{
-			ID:         m.ID,
-			FileURL:    m.FileURL,
-			FileType:   m.FileType,
-			OrderIndex: m.OrderIndex,
-		}
+		media[i] = mediaToDTO(&m)
 	}
 
 	return &dto.EventResponse{
-		ID:              event.ID,
-		Title:           event.Title,
-		Description:     event.Description,
-		EventDate:       event.EventDate,
-		Latitude:        event.Latitude,
-		Longitude:       event.Longitude,
-		Type:            event.Type,
-		MaxParticipants: event.MaxParticipants,
-		Price:           event.Price,
-		Address:         event.Address,
-		IsVerified:      event.IsVerified,
-		IsActive:        event.IsActive,
-		CreatorID:       event.CreatorID,
-		Creator: dto.UserShort{
-			ID:       event.Creator.ID,
-			Username: event.Creator.Username,
-			Email:    event.Creator.Email,
-			Role:     event.Creator.Role,
-		},
+		ID:                event.ID,
+		Title:             event.Title,
+		Description:       event.Description,
+		EventDate:         event.EventDate,
+		Latitude:          event.Latitude,
+		Longitude:         event.Longitude,
+		Type:              event.Type,
+		MaxParticipants:   event.MaxParticipants,
+		Price:             event.Price,
+		Address:           event.Address,
+		IsVerified:        event.IsVerified,
+		IsActive:          event.IsActive,
+		IsCancelled:       event.IsCancelled(),
+		CancelledAt:       event.CancelledAt,
+		CancelReason:      event.CancelReason,
+		CreatorID:         event.CreatorID,
+		Creator:           userToShort(&event.Creator),
 		ParticipantsCount: event.ParticipantsCount,
+		RSVPCounts:        event.RSVPCounts,
+		DistanceKm:        event.DistanceKm,
 		CreatedAt:         event.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:         event.UpdatedAt.Format(time.RFC3339),
 		Tags:              tags,
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+)
+
+// LoginGuardSettings пороги защиты от перебора паролей
+type LoginGuardSettings struct {
+	// Сколько неудач подряд допускается до начала задержек
+	BackoffThreshold int
+	BackoffBase      time.Duration
+	BackoffMax       time.Duration
+	// После стольких неудач подряд аккаунт блокируется на LockDuration
+	LockThreshold int
+	LockDuration  time.Duration
+	// Порог задержек для одного IP; считается за FailureWindow
+	IPBackoffThreshold int
+	FailureWindow      time.Duration
+}
+
+// LoginGuard ведет журнал входов и ограничивает частоту попыток по аккаунту и по IP
+type LoginGuard struct {
+	attemptRepo      appInterfaces.LoginAttemptRepository
+	userRepo         appInterfaces.UserRepository
+	notificationRepo appInterfaces.NotificationRepository
+	settings         LoginGuardSettings
+}
+
+func NewLoginGuard(
+	attemptRepo appInterfaces.LoginAttemptRepository,
+	userRepo appInterfaces.UserRepository,
+	notificationRepo appInterfaces.NotificationRepository,
+	settings LoginGuardSettings,
+) *LoginGuard {
+	return &LoginGuard{
+		attemptRepo:      attemptRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		settings:         settings,
+	}
+}
+
+// Begin начинает попытку входа и возвращает ее id для Fail/Succeed. Запись попытки и чтение
+// счетчиков атомарны, поэтому параллельные запросы не проскакивают мимо задержек.
+// Если попытку нужно отклонить без проверки пароля, возвращается LoginThrottledError
+func (g *LoginGuard) Begin(ctx context.Context, email, ip string, user *entities.User) (uint, error) {
+	now := time.Now()
+
+	if user != nil && user.IsLocked() {
+		g.record(ctx, email, ip, user, false, entities.LoginReasonLocked)
+		return 0, &entities.LoginThrottledError{RetryAfter: user.LockedUntil.Sub(now), Locked: true}
+	}
+
+	attempt := &entities.LoginAttempt{
+		Email:     normalizeEmail(email),
+		IP:        ip,
+		CreatedAt: now,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	failures, err := g.attemptRepo.Begin(ctx, attempt, now.Add(-g.settings.FailureWindow))
+	if err != nil {
+		return 0, err
+	}
+
+	wait := g.retryAfter(failures.Account, g.settings.BackoffThreshold, failures.AccountLast, now)
+	if ipWait := g.retryAfter(failures.IP, g.settings.IPBackoffThreshold, failures.IPLast, now); ipWait > wait {
+		wait = ipWait
+	}
+	if wait > 0 {
+		g.finish(ctx, attempt.ID, false, entities.LoginReasonThrottled)
+		return 0, &entities.LoginThrottledError{RetryAfter: wait}
+	}
+
+	return attempt.ID, nil
+}
+
+// Fail завершает попытку неудачей и блокирует аккаунт при превышении порога
+func (g *LoginGuard) Fail(ctx context.Context, attemptID uint, email string, user *entities.User, reason string) {
+	g.finish(ctx, attemptID, false, reason)
+
+	if user == nil || (reason != entities.LoginReasonInvalidCredentials && reason != entities.LoginReasonInvalidOTP) {
+		return
+	}
+
+	failures, _, err := g.attemptRepo.AccountFailures(ctx, normalizeEmail(email), time.Now().Add(-g.settings.FailureWindow))
+	if err != nil || failures < int64(g.settings.LockThreshold) {
+		return
+	}
+
+	lockedUntil := time.Now().Add(g.settings.LockDuration)
+	if err := g.userRepo.SetLockedUntil(ctx, user.ID, &lockedUntil); err != nil {
+		log.Printf("failed to lock user %d: %v", user.ID, err)
+		return
+	}
+
+	g.notificationRepo.Create(ctx, &entities.Notification{
+		UserID:    user.ID,
+		Message:   "Аккаунт временно заблокирован из-за множества неудачных попыток входа",
+		Type:      "security",
+		Read:      false,
+		CreatedAt: time.Now(),
+	})
+}
+
+// PassFirstFactor отмечает верный пароль; попытка больше не считается неудачной,
+// но и счетчик не сбрасывает, пока не введен второй фактор
+func (g *LoginGuard) PassFirstFactor(ctx context.Context, attemptID uint) {
+	g.finish(ctx, attemptID, false, entities.LoginReasonSecondFactor)
+}
+
+// Succeed завершает попытку успехом; счетчик неудач аккаунта после него начинается заново.
+// Без начатой попытки (attemptID равен 0, например вход через провайдера) пишется новая запись
+func (g *LoginGuard) Succeed(ctx context.Context, attemptID uint, email, ip string, user *entities.User) {
+	if attemptID == 0 {
+		g.record(ctx, email, ip, user, true, entities.LoginReasonSuccess)
+		return
+	}
+	g.finish(ctx, attemptID, true, entities.LoginReasonSuccess)
+}
+
+// Confirm завершает повторную проверку пароля или кода перед важным действием (например,
+// отключением 2FA); как и успешный вход, она сбрасывает счетчик неудач
+func (g *LoginGuard) Confirm(ctx context.Context, attemptID uint) {
+	g.finish(ctx, attemptID, true, entities.LoginReasonConfirmed)
+}
+
+func (g *LoginGuard) finish(ctx context.Context, attemptID uint, success bool, reason string) {
+	if err := g.attemptRepo.Finish(ctx, attemptID, success, reason); err != nil {
+		log.Printf("failed to finish login attempt %d: %v", attemptID, err)
+	}
+}
+
+func (g *LoginGuard) record(ctx context.Context, email, ip string, user *entities.User, success bool, reason string) {
+	attempt := &entities.LoginAttempt{
+		Email:     normalizeEmail(email),
+		IP:        ip,
+		Success:   success,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	if err := g.attemptRepo.Create(ctx, attempt); err != nil {
+		log.Printf("failed to record login attempt: %v", err)
+	}
+}
+
+// retryAfter экспоненциальная задержка: base * 2^(failures-threshold), но не больше max
+func (g *LoginGuard) retryAfter(failures int64, threshold int, last, now time.Time) time.Duration {
+	if threshold <= 0 || failures < int64(threshold) {
+		return 0
+	}
+
+	delay := g.settings.BackoffBase
+	for i := int64(threshold); i < failures && delay < g.settings.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > g.settings.BackoffMax {
+		delay = g.settings.BackoffMax
+	}
+
+	return last.Add(delay).Sub(now)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"strings"
+
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+	"auth-system/internal/pkg/imaging"
+)
+
+// originalsPrefix префикс ключей исходников, ожидающих обработки
+const originalsPrefix = "originals/"
+
+// MediaProcessor в фоне строит уменьшенные копии фотографий и удаляет из них метаданные.
+// Исходники с EXIF лежат в отдельном закрытом хранилище originals и наружу не раздаются
+type MediaProcessor struct {
+	eventRepo appInterfaces.EventRepository
+	storage   appInterfaces.MediaStorage
+	originals appInterfaces.MediaStorage
+	jobs      chan uint
+	workers   int
+}
+
+func NewMediaProcessor(eventRepo appInterfaces.EventRepository, storage, originals appInterfaces.MediaStorage, workers int) *MediaProcessor {
+	return &MediaProcessor{
+		eventRepo: eventRepo,
+		storage:   storage,
+		originals: originals,
+		jobs:      make(chan uint, 100),
+		workers:   workers,
+	}
+}
+
+// Start запускает воркеры и возвращает в очередь файлы, не обработанные до перезапуска
+func (p *MediaProcessor) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		go p.run(ctx)
+	}
+
+	pending, err := p.eventRepo.GetMediaByStatus(ctx, entities.MediaStatusProcessing)
+	if err != nil {
+		log.Printf("Media processor: failed to load pending media: %v", err)
+		return
+	}
+	for _, media := range pending {
+		p.Enqueue(ctx, media.ID)
+	}
+}
+
+// Enqueue ставит файл в очередь, не блокируя обработчик запроса
+func (p *MediaProcessor) Enqueue(ctx context.Context, mediaID uint) {
+	select {
+	case p.jobs <- mediaID:
+	default:
+		go func() {
+			select {
+			case p.jobs <- mediaID:
+			case <-ctx.Done():
+			}
+		}()
+	}
+}
+
+func (p *MediaProcessor) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case mediaID := <-p.jobs:
+			if err := p.process(ctx, mediaID); err != nil {
+				log.Printf("Media processor: media %d: %v", mediaID, err)
+			}
+		}
+	}
+}
+
+func (p *MediaProcessor) process(ctx context.Context, mediaID uint) error {
+	media, err := p.eventRepo.FindMediaByID(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+	if media.Status != entities.MediaStatusProcessing {
+		return nil
+	}
+
+	urls, err := p.buildVariants(ctx, media.StorageKey)
+	if err != nil {
+		media.Status = entities.MediaStatusFailed
+		if _, updateErr := p.eventRepo.FinishMediaProcessing(ctx, media); updateErr != nil {
+			log.Printf("Media processor: failed to mark media %d as failed: %v", mediaID, updateErr)
+		}
+		// Исходник с метаданными не храним и при ошибке, как и недостроенные копии
+		p.discard(ctx, media)
+		return err
+	}
+
+	media.FileURL = urls[imaging.Full.Name]
+	media.CardURL = urls[imaging.Card.Name]
+	media.ThumbnailURL = urls[imaging.Thumbnail.Name]
+	media.FileType = "image/jpeg"
+	media.Status = entities.MediaStatusReady
+	updated, err := p.eventRepo.FinishMediaProcessing(ctx, media)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		// Фотографию удалили во время обработки: только что загруженные копии никому не нужны
+		p.discard(ctx, media)
+		return nil
+	}
+
+	// Исходник с метаданными больше не нужен
+	return p.originals.Delete(ctx, media.StorageKey)
+}
+
+// discard удаляет копии и исходник фотографии, которая не будет опубликована
+func (p *MediaProcessor) discard(ctx context.Context, media *entities.EventMedia) {
+	for _, variant := range imaging.Variants {
+		p.storage.Delete(ctx, variantKey(media.StorageKey, variant))
+	}
+	if err := p.originals.Delete(ctx, media.StorageKey); err != nil {
+		log.Printf("Media processor: failed to delete original of media %d: %v", media.ID, err)
+	}
+}
+
+func (p *MediaProcessor) buildVariants(ctx context.Context, originalKey string) (map[string]string, error) {
+	src, err := p.originals.Open(ctx, originalKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(src)
+	src.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make(map[string]string, len(imaging.Variants))
+	for _, variant := range imaging.Variants {
+		var buf bytes.Buffer
+		if err := imaging.EncodeJPEG(&buf, imaging.Resize(img, variant.MaxSize)); err != nil {
+			return nil, err
+		}
+
+		url, err := p.storage.Upload(ctx, variantKey(originalKey, variant), &buf, int64(buf.Len()), "image/jpeg")
+		if err != nil {
+			return nil, err
+		}
+		urls[variant.Name] = url
+	}
+	return urls, nil
+}
+
+// variantKey строит ключ копии: originals/events/1/abc.png -> events/1/abc_thumb.jpg
+func variantKey(originalKey string, variant imaging.Variant) string {
+	base := strings.TrimPrefix(originalKey, originalsPrefix)
+	if dot := strings.LastIndex(base, "."); dot > strings.LastIndex(base, "/") {
+		base = base[:dot]
+	}
+	return base + "_" + variant.Name + ".jpg"
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"auth-system/internal/application/dto"
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+	"auth-system/internal/pkg/imaging"
+)
+
+// maxMediaPerEvent ограничение галереи одного мероприятия
+const maxMediaPerEvent = 20
+
+// allowedMediaTypes допустимые типы файлов и расширения для ключей хранилища
+var allowedMediaTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+	"video/webm": ".webm",
+}
+
+// processedImageTypes фотографии, для которых строятся уменьшенные копии без EXIF
+var processedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type MediaService struct {
+	eventRepo   appInterfaces.EventRepository
+	storage     appInterfaces.MediaStorage
+	originals   appInterfaces.MediaStorage
+	processor   *MediaProcessor
+	maxFileSize int64
+}
+
+func NewMediaService(eventRepo appInterfaces.EventRepository, storage, originals appInterfaces.MediaStorage, processor *MediaProcessor, maxFileSize int64) *MediaService {
+	return &MediaService{
+		eventRepo:   eventRepo,
+		storage:     storage,
+		originals:   originals,
+		processor:   processor,
+		maxFileSize: maxFileSize,
+	}
+}
+
+func (s *MediaService) UploadMedia(ctx context.Context, eventID, userID uint, files []*multipart.FileHeader) ([]dto.Media, error) {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, entities.ErrEventNotFound
+	}
+
+	if !event.CanEdit(userID) {
+		return nil, fmt.Errorf("%w to upload media for this event", entities.ErrNotAuthorized)
+	}
+
+	if len(files) == 0 {
+		return nil, &entities.MediaValidationError{Reason: "no files provided"}
+	}
+	if len(event.Media)+len(files) > maxMediaPerEvent {
+		return nil, &entities.MediaValidationError{Reason: fmt.Sprintf("event can have at most %d media files", maxMediaPerEvent)}
+	}
+
+	// Проверяем размер и тип всех файлов до загрузки, чтобы не оставлять частично загруженную галерею
+	contentTypes := make([]string, len(files))
+	for i, file := range files {
+		if file.Size > s.maxFileSize {
+			return nil, &entities.MediaValidationError{Reason: fmt.Sprintf("file %s exceeds the %d MB limit", file.Filename, s.maxFileSize>>20)}
+		}
+		if contentTypes[i], err = detectMediaType(file); err != nil {
+			return nil, err
+		}
+	}
+
+	nextIndex := 0
+	for _, m := range event.Media {
+		if m.OrderIndex >= nextIndex {
+			nextIndex = m.OrderIndex + 1
+		}
+	}
+
+	response := make([]dto.Media, 0, len(files))
+	for i, file := range files {
+		media, err := s.uploadFile(ctx, eventID, file, contentTypes[i], nextIndex)
+		if err != nil {
+			return nil, err
+		}
+		nextIndex++
+		response = append(response, mediaToDTO(media))
+	}
+
+	return response, nil
+}
+
+// detectMediaType определяет тип по содержимому, а не по расширению или заголовку клиента
+func detectMediaType(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if _, ok := allowedMediaTypes[contentType]; !ok {
+		return "", &entities.MediaValidationError{Reason: fmt.Sprintf("file %s has unsupported type %s", file.Filename, contentType)}
+	}
+	return contentType, nil
+}
+
+func (s *MediaService) uploadFile(ctx context.Context, eventID uint, file *multipart.FileHeader, contentType string, orderIndex int) (*entities.EventMedia, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	key, err := mediaKey(eventID, allowedMediaTypes[contentType])
+	if err != nil {
+		return nil, err
+	}
+
+	// Фотографии сначала попадают в закрытое хранилище исходников и публикуются только после обработки
+	target := s.storage
+	process := processedImageTypes[contentType]
+	if process {
+		key = originalsPrefix + key
+		target = s.originals
+	}
+
+	url, err := target.Upload(ctx, key, src, file.Size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	media := &entities.EventMedia{
+		EventID:    eventID,
+		FileURL:    url,
+		FileType:   contentType,
+		StorageKey: key,
+		Status:     entities.MediaStatusReady,
+		OrderIndex: orderIndex,
+		UploadedAt: time.Now(),
+		CreatedAt:  time.Now(),
+	}
+	if process {
+		media.FileURL = ""
+		media.Status = entities.MediaStatusProcessing
+	}
+
+	if err := s.eventRepo.AddMedia(ctx, media); err != nil {
+		target.Delete(ctx, key)
+		return nil, err
+	}
+
+	if process {
+		s.processor.Enqueue(context.WithoutCancel(ctx), media.ID)
+	}
+
+	return media, nil
+}
+
+func (s *MediaService) ReorderMedia(ctx context.Context, eventID, userID uint, req dto.ReorderMediaRequest) ([]dto.Media, error) {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return nil, entities.ErrEventNotFound
+	}
+
+	if !event.CanEdit(userID) {
+		return nil, fmt.Errorf("%w to reorder media for this event", entities.ErrNotAuthorized)
+	}
+
+	// Новый порядок должен содержать каждый файл мероприятия ровно один раз
+	if len(req.MediaIDs) != len(event.Media) {
+		return nil, &entities.MediaValidationError{Reason: "media_ids must list every media file of the event"}
+	}
+	existing := make(map[uint]bool, len(event.Media))
+	for _, m := range event.Media {
+		existing[m.ID] = true
+	}
+	for _, id := range req.MediaIDs {
+		if !existing[id] {
+			return nil, &entities.MediaValidationError{Reason: fmt.Sprintf("media %d does not belong to the event or is duplicated", id)}
+		}
+		delete(existing, id)
+	}
+
+	if err := s.eventRepo.ReorderMedia(ctx, eventID, req.MediaIDs); err != nil {
+		return nil, err
+	}
+
+	media, err := s.eventRepo.GetMedia(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.Media, len(media))
+	for i, m := range media {
+		response[i] = mediaToDTO(&m)
+	}
+	return response, nil
+}
+
+func (s *MediaService) DeleteMedia(ctx context.Context, eventID, mediaID, userID uint) error {
+	event, err := s.eventRepo.FindByID(ctx, eventID)
+	if err != nil {
+		return entities.ErrEventNotFound
+	}
+
+	if !event.CanEdit(userID) {
+		return fmt.Errorf("%w to delete media for this event", entities.ErrNotAuthorized)
+	}
+
+	media, err := s.eventRepo.FindMediaByID(ctx, mediaID)
+	if err != nil || media.EventID != eventID {
+		return entities.ErrMediaNotFound
+	}
+
+	if err := s.eventRepo.DeleteMedia(ctx, mediaID); err != nil {
+		return err
+	}
+
+	if media.StorageKey == "" {
+		return nil
+	}
+
+	// У фотографий удаляем все копии и исходник, если он еще не обработан
+	if strings.HasPrefix(media.StorageKey, originalsPrefix) {
+		for _, variant := range imaging.Variants {
+			if err := s.storage.Delete(ctx, variantKey(media.StorageKey, variant)); err != nil {
+				return err
+			}
+		}
+		return s.originals.Delete(ctx, media.StorageKey)
+	}
+	return s.storage.Delete(ctx, media.StorageKey)
+}
+
+// mediaKey формирует непредсказуемый ключ объекта в хранилище
+func mediaKey(eventID uint, ext string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("events/%d/%s%s", eventID, hex.EncodeToString(buf), ext), nil
+}
+
+func mediaToDTO(m *entities.EventMedia) dto.Media {
+	media := dto.Media{
+		ID:         m.ID,
+		FileURL:    m.FileURL,
+		FileType:   m.FileType,
+		Status:     m.Status,
+		OrderIndex: m.OrderIndex,
+	}
+	if m.ThumbnailURL != "" {
+		media.Sizes = &dto.MediaSizes{
+			Thumbnail: m.ThumbnailURL,
+			Card:      m.CardURL,
+			Full:      m.FileURL,
+		}
+	}
+	return media
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"auth-system/internal/application/dto"
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+	"auth-system/internal/pkg/utils"
+)
+
+// oauthStateTTL сколько пользователь может провести на странице провайдера
+const oauthStateTTL = 10 * time.Minute
+
+var usernameCleaner = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// OAuthService вход и привязка аккаунтов внешних провайдеров (OIDC/OAuth2)
+type OAuthService struct {
+	providers    map[string]appInterfaces.OAuthProvider
+	identityRepo appInterfaces.IdentityRepository
+	userRepo     appInterfaces.UserRepository
+	authService  *AuthService
+}
+
+func NewOAuthService(
+	providers []appInterfaces.OAuthProvider,
+	identityRepo appInterfaces.IdentityRepository,
+	userRepo appInterfaces.UserRepository,
+	authService *AuthService,
+) *OAuthService {
+	byName := make(map[string]appInterfaces.OAuthProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &OAuthService{
+		providers:    byName,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+	}
+}
+
+// Providers возвращает имена подключенных провайдеров
+func (s *OAuthService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartLogin возвращает адрес страницы провайдера для входа
+func (s *OAuthService) StartLogin(ctx context.Context, providerName string) (*dto.OAuthStartResponse, error) {
+	return s.start(ctx, providerName, nil)
+}
+
+// StartLink возвращает адрес страницы провайдера для привязки к текущему аккаунту
+func (s *OAuthService) StartLink(ctx context.Context, providerName string, userID uint) (*dto.OAuthStartResponse, error) {
+	return s.start(ctx, providerName, &userID)
+}
+
+func (s *OAuthService) start(ctx context.Context, providerName string, userID *uint) (*dto.OAuthStartResponse, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, errors.New("unknown provider")
+	}
+
+	state, stateHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, challenge, err := utils.GeneratePKCE()
+	if err != nil {
+		return nil, err
+	}
+
+	// Заодно чистим брошенные попытки
+	s.identityRepo.DeleteExpiredStates(ctx)
+
+	if err := s.identityRepo.CreateState(ctx, &entities.OAuthState{
+		StateHash:    stateHash,
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(oauthStateTTL),
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &dto.OAuthStartResponse{AuthorizationURL: provider.AuthCodeURL(state, challenge)}, nil
+}
+
+// Callback завершает вход: проверяет state, меняет код на профиль и находит,
+// связывает или создает пользователя
+func (s *OAuthService) Callback(ctx context.Context, providerName string, req dto.OAuthCallbackRequest, clientIP string) (*dto.AuthResponse, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, errors.New("unknown provider")
+	}
+
+	state, err := s.identityRepo.ConsumeState(ctx, utils.HashToken(req.State))
+	if err != nil || state.Provider != providerName || state.IsExpired() {
+		return nil, errors.New("invalid or expired state")
+	}
+
+	callback := url.Values{}
+	callback.Set("state", req.State)
+	if req.DeviceID != "" {
+		callback.Set("device_id", req.DeviceID)
+	}
+
+	profile, err := provider.Exchange(ctx, req.Code, state.CodeVerifier, callback)
+	if err != nil {
+		log.Printf("oauth exchange failed: %v", err)
+		return nil, errors.New("failed to authenticate with provider")
+	}
+
+	user, err := s.resolveUser(ctx, profile, state.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsBlocked {
+		return nil, errors.New("account is blocked")
+	}
+
+	return s.authService.beginSession(ctx, user, user.Email, clientIP, 0)
+}
+
+func (s *OAuthService) resolveUser(ctx context.Context, profile *appInterfaces.ExternalProfile, linkUserID *uint) (*entities.User, error) {
+	identity, err := s.identityRepo.FindByProviderSubject(ctx, profile.Provider, profile.Subject)
+	if err == nil {
+		if linkUserID != nil && *linkUserID != identity.UserID {
+			return nil, errors.New("this account is already linked to another user")
+		}
+		s.identityRepo.TouchLogin(ctx, identity.ID)
+		return s.userRepo.FindByID(ctx, identity.UserID)
+	}
+
+	// Привязка из профиля: пользователь уже вошел и подтвердил владение аккаунтом провайдера
+	if linkUserID != nil {
+		user, err := s.userRepo.FindByID(ctx, *linkUserID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+		return user, s.link(ctx, user, profile)
+	}
+
+	if profile.Email != "" {
+		existing, err := s.userRepo.FindByEmail(ctx, profile.Email)
+		if err == nil {
+			// Связываем автоматически, только если провайдер подтвердил адрес
+			if !profile.EmailVerified {
+				return nil, errors.New("an account with this email already exists, sign in and link the provider from your profile")
+			}
+			if !existing.EmailVerified {
+				if err := s.claimUnverified(ctx, existing); err != nil {
+					return nil, err
+				}
+			}
+			return existing, s.link(ctx, existing, profile)
+		}
+	}
+
+	return s.createUser(ctx, profile)
+}
+
+// claimUnverified передает неподтвержденный аккаунт владельцу почты. Такой аккаунт
+// мог зарегистрировать кто угодно на чужой адрес, поэтому все, что задал прежний
+// регистрант (пароль, сессии, второй фактор, привязки), сбрасывается
+func (s *OAuthService) claimUnverified(ctx context.Context, user *entities.User) error {
+	identities, err := s.identityRepo.FindByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	for _, identity := range identities {
+		if err := s.identityRepo.Delete(ctx, user.ID, identity.Provider); err != nil {
+			return err
+		}
+	}
+
+	user.EmailVerified = true
+	return s.authService.resetCredentials(ctx, user)
+}
+
+func (s *OAuthService) createUser(ctx context.Context, profile *appInterfaces.ExternalProfile) (*entities.User, error) {
+	if profile.Email == "" {
+		return nil, errors.New("provider did not share an email address")
+	}
+
+	username, err := s.uniqueUsername(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Пароль не задан: войти по паролю можно после сброса через почту
+	user := &entities.User{
+		Username:      username,
+		Email:         profile.Email,
+		Role:          entities.RoleUser,
+		AvatarURL:     profile.AvatarURL,
+		EmailVerified: profile.EmailVerified,
+		LastOnline:    time.Now(),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, s.link(ctx, user, profile)
+}
+
+func (s *OAuthService) link(ctx context.Context, user *entities.User, profile *appInterfaces.ExternalProfile) error {
+	now := time.Now()
+	return s.identityRepo.Create(ctx, &entities.UserIdentity{
+		UserID:      user.ID,
+		Provider:    profile.Provider,
+		Subject:     profile.Subject,
+		Email:       profile.Email,
+		CreatedAt:   now,
+		LastLoginAt: &now,
+	})
+}
+
+// uniqueUsername строит имя из профиля и добавляет номер, если оно занято
+func (s *OAuthService) uniqueUsername(ctx context.Context, profile *appInterfaces.ExternalProfile) (string, error) {
+	base := usernameCleaner.ReplaceAllString(strings.ReplaceAll(profile.Name, " ", "_"), "")
+	if len(base) < 3 {
+		base = usernameCleaner.ReplaceAllString(strings.SplitN(profile.Email, "@", 2)[0], "")
+	}
+	if len(base) < 3 {
+		base = profile.Provider + "_user"
+	}
+
+	candidate := base
+	for i := 1; i <= 100; i++ {
+		if _, err := s.userRepo.FindByUsername(ctx, candidate); err != nil {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+	return "", errors.New("failed to pick a username")
+}
+
+func (s *OAuthService) GetIdentities(ctx context.Context, userID uint) ([]dto.IdentityResponse, error) {
+	identities, err := s.identityRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.IdentityResponse, len(identities))
+	for i, identity := range identities {
+		response[i] = dto.IdentityResponse{
+			Provider:  identity.Provider,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt.Format(time.RFC3339),
+		}
+		if identity.LastLoginAt != nil {
+			response[i].LastLoginAt = identity.LastLoginAt.Format(time.RFC3339)
+		}
+	}
+	return response, nil
+}
+
+// Unlink отвязывает провайдера; последний способ входа отвязать нельзя
+func (s *OAuthService) Unlink(ctx context.Context, userID uint, providerName string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	identities, err := s.identityRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PasswordHash == "" && len(identities) <= 1 {
+		return errors.New("set a password before unlinking the last sign-in method")
+	}
+
+	if err := s.identityRepo.Delete(ctx, userID, providerName); err != nil {
+		return errors.New("provider is not linked")
+	}
+	return nil
+}
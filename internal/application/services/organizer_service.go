@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth-system/internal/application/dto"
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+)
+
+// OrganizerService ведет заявки на статус проверенного организатора.
+// Мероприятия проверенных организаторов публикуются без ручной верификации.
+type OrganizerService struct {
+	applicationRepo  appInterfaces.OrganizerApplicationRepository
+	userRepo         appInterfaces.UserRepository
+	adminRepo        appInterfaces.AdminRepository
+	notificationRepo appInterfaces.NotificationRepository
+	accessChecker    appInterfaces.AccessChecker
+}
+
+func NewOrganizerService(
+	applicationRepo appInterfaces.OrganizerApplicationRepository,
+	userRepo appInterfaces.UserRepository,
+	adminRepo appInterfaces.AdminRepository,
+	notificationRepo appInterfaces.NotificationRepository,
+	accessChecker appInterfaces.AccessChecker,
+) *OrganizerService {
+	return &OrganizerService{
+		applicationRepo:  applicationRepo,
+		userRepo:         userRepo,
+		adminRepo:        adminRepo,
+		notificationRepo: notificationRepo,
+		accessChecker:    accessChecker,
+	}
+}
+
+func (s *OrganizerService) Apply(ctx context.Context, userID uint, req dto.OrganizerApplicationRequest) (*dto.OrganizerApplicationResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !user.EmailVerified {
+		return nil, errors.New("email is not verified")
+	}
+
+	if user.PublishesWithoutReview() {
+		return nil, errors.New("user is already verified")
+	}
+
+	if latest, err := s.applicationRepo.FindLatestByUser(ctx, userID); err == nil && latest.IsPending() {
+		return nil, errors.New("application is already pending")
+	}
+
+	application := &entities.OrganizerApplication{
+		UserID:    userID,
+		Message:   req.Message,
+		Status:    entities.ApplicationStatusPending,
+		CreatedAt: time.Now(),
+		User:      *user,
+	}
+	if err := s.applicationRepo.Create(ctx, application); err != nil {
+		return nil, err
+	}
+
+	// Уведомляем тех, кто рассматривает заявки
+	reviewers, err := s.userRepo.GetByRoles(ctx, entities.RolesWithPermission(entities.PermOrganizersVerify))
+	if err == nil {
+		for _, reviewer := range reviewers {
+			s.notificationRepo.Create(ctx, &entities.Notification{
+				UserID:    reviewer.ID,
+				Message:   "Новая заявка на статус организатора от " + user.Username,
+				Type:      "organizer_application",
+				Read:      false,
+				CreatedAt: time.Now(),
+			})
+		}
+	}
+
+	return applicationToDTO(application), nil
+}
+
+func (s *OrganizerService) GetMyApplication(ctx context.Context, userID uint) (*dto.OrganizerApplicationResponse, error) {
+	application, err := s.applicationRepo.FindLatestByUser(ctx, userID)
+	if err != nil {
+		return nil, errors.New("application not found")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	application.User = *user
+
+	return applicationToDTO(application), nil
+}
+
+func (s *OrganizerService) GetApplications(ctx context.Context, query dto.OrganizerApplicationQuery) ([]dto.OrganizerApplicationResponse, error) {
+	applications, err := s.applicationRepo.FindByStatus(ctx, query.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.OrganizerApplicationResponse, len(applications))
+	for i, application := range applications {
+		response[i] = *applicationToDTO(&application)
+	}
+	return response, nil
+}
+
+func (s *OrganizerService) ApproveApplication(ctx context.Context, applicationID, adminID uint, req dto.ReviewApplicationRequest) error {
+	application, err := s.reviewApplication(ctx, applicationID, adminID, entities.ApplicationStatusApproved, req.Note)
+	if err != nil {
+		return err
+	}
+
+	// Отметка о проверке ставится при любой роли, а роль повышается только у обычных
+	// пользователей: сотрудники уже публикуют без проверки, понижать их роль не нужно
+	verifiedAt := time.Now()
+	if err := s.userRepo.SetOrganizerVerified(ctx, application.UserID, &verifiedAt); err != nil {
+		return err
+	}
+	if application.User.Role == entities.RoleUser {
+		if err := s.userRepo.UpdateRole(ctx, application.UserID, entities.RoleOrganizer); err != nil {
+			return err
+		}
+		s.accessChecker.Invalidate(application.UserID)
+	}
+
+	s.adminRepo.LogAction(ctx, &entities.AdminAction{
+		AdminID:     adminID,
+		ActionType:  "approve_organizer",
+		TargetID:    application.UserID,
+		TargetType:  "user",
+		Reason:      req.Note,
+		PerformedAt: time.Now(),
+	})
+
+	s.notificationRepo.Create(ctx, &entities.Notification{
+		UserID:    application.UserID,
+		Message:   "Ваша заявка одобрена: теперь вы проверенный организатор",
+		Type:      "organizer_approved",
+		Read:      false,
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+func (s *OrganizerService) RejectApplication(ctx context.Context, applicationID, adminID uint, req dto.ReviewApplicationRequest) error {
+	application, err := s.reviewApplication(ctx, applicationID, adminID, entities.ApplicationStatusRejected, req.Note)
+	if err != nil {
+		return err
+	}
+
+	s.adminRepo.LogAction(ctx, &entities.AdminAction{
+		AdminID:     adminID,
+		ActionType:  "reject_organizer",
+		TargetID:    application.UserID,
+		TargetType:  "user",
+		Reason:      req.Note,
+		PerformedAt: time.Now(),
+	})
+
+	message := "Ваша заявка на статус организатора отклонена"
+	if req.Note != "" {
+		message += ". Причина: " + req.Note
+	}
+	s.notificationRepo.Create(ctx, &entities.Notification{
+		UserID:    application.UserID,
+		Message:   message,
+		Type:      "organizer_rejected",
+		Read:      false,
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+// RevokeVerification снимает статус проверенного организатора
+func (s *OrganizerService) RevokeVerification(ctx context.Context, userID, adminID uint, req dto.RevokeOrganizerRequest) error {
+	if _, err := authorize(ctx, s.userRepo, adminID, entities.PermOrganizersVerify); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !user.IsVerifiedOrganizer() {
+		return errors.New("user is not a verified organizer")
+	}
+
+	if err := s.userRepo.SetOrganizerVerified(ctx, userID, nil); err != nil {
+		return err
+	}
+	// Роль выдавалась вместе с проверкой, поэтому организатор снова становится обычным пользователем
+	if user.Role == entities.RoleOrganizer {
+		if err := s.userRepo.UpdateRole(ctx, userID, entities.RoleUser); err != nil {
+			return err
+		}
+		s.accessChecker.Invalidate(userID)
+	}
+
+	s.adminRepo.LogAction(ctx, &entities.AdminAction{
+		AdminID:     adminID,
+		ActionType:  "revoke_organizer",
+		TargetID:    userID,
+		TargetType:  "user",
+		Reason:      req.Reason,
+		PerformedAt: time.Now(),
+	})
+
+	message := "Статус проверенного организатора снят администратором"
+	if req.Reason != "" {
+		message += ". Причина: " + req.Reason
+	}
+	s.notificationRepo.Create(ctx, &entities.Notification{
+		UserID:    userID,
+		Message:   message,
+		Type:      "organizer_revoked",
+		Read:      false,
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+func (s *OrganizerService) reviewApplication(ctx context.Context, applicationID, adminID uint, status, note string) (*entities.OrganizerApplication, error) {
+	if _, err := authorize(ctx, s.userRepo, adminID, entities.PermOrganizersVerify); err != nil {
+		return nil, err
+	}
+
+	application, err := s.applicationRepo.FindByID(ctx, applicationID)
+	if err != nil {
+		return nil, errors.New("application not found")
+	}
+
+	if !application.IsPending() {
+		return nil, errors.New("application is already reviewed")
+	}
+
+	now := time.Now()
+	application.Status = status
+	application.ReviewedBy = &adminID
+	application.ReviewNote = note
+	application.ReviewedAt = &now
+	if err := s.applicationRepo.Update(ctx, application); err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
+func applicationToDTO(application *entities.OrganizerApplication) *dto.OrganizerApplicationResponse {
+	response := &dto.OrganizerApplicationResponse{
+		ID:         application.ID,
+		User:       userToShort(&application.User),
+		Message:    application.Message,
+		Status:     application.Status,
+		ReviewNote: application.ReviewNote,
+		CreatedAt:  application.CreatedAt.Format(time.RFC3339),
+	}
+	if application.ReviewedAt != nil {
+		response.ReviewedAt = application.ReviewedAt.Format(time.RFC3339)
+	}
+	return response
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"auth-system/internal/application/dto"
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/pkg/imaging"
+)
+
+// profileColumns поля, которыми владеет редактирование профиля; остальные колонки
+// (роль, блокировка, пароль, 2FA) меняются своими запросами и здесь не перезаписываются
+var profileColumns = []string{"username", "bio", "hide_participation", "avatar_url", "avatar_key", "updated_at"}
+
+// avatarTypes форматы, из которых принимается аватар; хранится он всегда в JPEG
+var avatarTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type ProfileService struct {
+	userRepo      appInterfaces.UserRepository
+	commentRepo   appInterfaces.CommentRepository
+	storage       appInterfaces.MediaStorage
+	authService   *AuthService
+	eventService  *EventService
+	maxAvatarSize int64
+}
+
+func NewProfileService(
+	userRepo appInterfaces.UserRepository,
+	commentRepo appInterfaces.CommentRepository,
+	storage appInterfaces.MediaStorage,
+	authService *AuthService,
+	eventService *EventService,
+	maxAvatarSize int64,
+) *ProfileService {
+	return &ProfileService{
+		userRepo:      userRepo,
+		commentRepo:   commentRepo,
+		storage:       storage,
+		authService:   authService,
+		eventService:  eventService,
+		maxAvatarSize: maxAvatarSize,
+	}
+}
+
+// GetPublicProfile профиль для других пользователей; viewerID равен 0 для гостя.
+// Скрытое участие видит только сам владелец.
+func (s *ProfileService) GetPublicProfile(ctx context.Context, userID, viewerID uint) (*dto.PublicProfileResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil || user.IsBlocked {
+		return nil, errors.New("user not found")
+	}
+
+	commentsCount, karma, err := s.commentRepo.GetUserStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	createdEvents, err := s.eventService.GetUserEvents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.PublicProfileResponse{
+		ID:                  user.ID,
+		Username:            user.Username,
+		Role:                user.Role,
+		AvatarURL:           user.AvatarURL,
+		Bio:                 user.Bio,
+		IsVerifiedOrganizer: user.IsVerifiedOrganizer(),
+		CreatedAt:           user.CreatedAt.Format(time.RFC3339),
+		CommentsCount:       commentsCount,
+		Karma:               karma,
+		CreatedEvents:       createdEvents,
+		AttendedEvents:      []dto.EventResponse{},
+		ParticipationHidden: user.HideParticipation,
+	}
+
+	if !user.HideParticipation || viewerID == user.ID {
+		attended, err := s.eventService.GetParticipatedEvents(ctx, userID, dto.ParticipatedEventsQuery{})
+		if err != nil {
+			return nil, err
+		}
+		response.AttendedEvents = attended
+	}
+
+	// Профиль публичный: почта создателей мероприятий не раскрывается никому
+	for i := range response.CreatedEvents {
+		response.CreatedEvents[i].HidePrivateData()
+	}
+	for i := range response.AttendedEvents {
+		response.AttendedEvents[i].HidePrivateData()
+	}
+
+	return response, nil
+}
+
+// UpdateProfile меняет имя, описание и аватар. Новая почта начинает действовать
+// только после перехода по ссылке из письма на этот адрес.
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID uint, req dto.UpdateProfileRequest, avatar *multipart.FileHeader) (*dto.UpdateProfileResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if username != user.Username {
+			if len(username) < 3 {
+				return nil, errors.New("username must be at least 3 characters")
+			}
+			if _, err := s.userRepo.FindByUsername(ctx, username); err == nil {
+				return nil, errors.New("username already taken")
+			}
+			user.Username = username
+		}
+	}
+
+	if req.Bio != nil {
+		user.Bio = strings.TrimSpace(*req.Bio)
+	}
+
+	if req.HideParticipation != nil {
+		user.HideParticipation = *req.HideParticipation
+	}
+
+	pendingEmail := ""
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if !strings.EqualFold(email, user.Email) {
+			pendingEmail = email
+		}
+	}
+	// Письмо для смены адреса отправляем до сохранения: если оно не ушло,
+	// профиль остается прежним и запрос можно просто повторить
+	if pendingEmail != "" {
+		if err := s.authService.requestEmailChange(ctx, user, pendingEmail); err != nil {
+			return nil, err
+		}
+	}
+
+	oldAvatarKey := ""
+	if avatar != nil {
+		url, key, err := s.uploadAvatar(ctx, userID, avatar)
+		if err != nil {
+			s.cancelEmailChange(ctx, userID, pendingEmail)
+			return nil, err
+		}
+		oldAvatarKey = user.AvatarKey
+		user.AvatarURL = url
+		user.AvatarKey = key
+	}
+
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateColumns(ctx, user, profileColumns...); err != nil {
+		if avatar != nil {
+			s.storage.Delete(ctx, user.AvatarKey)
+		}
+		s.cancelEmailChange(ctx, userID, pendingEmail)
+		return nil, err
+	}
+
+	if oldAvatarKey != "" {
+		if err := s.storage.Delete(ctx, oldAvatarKey); err != nil {
+			log.Printf("failed to delete old avatar %s: %v", oldAvatarKey, err)
+		}
+	}
+
+	response := userToDTO(user)
+	return &dto.UpdateProfileResponse{User: &response, PendingEmail: pendingEmail}, nil
+}
+
+// cancelEmailChange отзывает уже отправленную ссылку смены адреса, если профиль не сохранился
+func (s *ProfileService) cancelEmailChange(ctx context.Context, userID uint, pendingEmail string) {
+	if pendingEmail == "" {
+		return
+	}
+	if err := s.authService.cancelEmailChange(ctx, userID); err != nil {
+		log.Printf("failed to cancel email change for user %d: %v", userID, err)
+	}
+}
+
+func (s *ProfileService) DeleteAvatar(ctx context.Context, userID uint) (*dto.UserResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	oldAvatarKey := user.AvatarKey
+	user.AvatarURL = ""
+	user.AvatarKey = ""
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.UpdateColumns(ctx, user, "avatar_url", "avatar_key", "updated_at"); err != nil {
+		return nil, err
+	}
+
+	if oldAvatarKey != "" {
+		if err := s.storage.Delete(ctx, oldAvatarKey); err != nil {
+			log.Printf("failed to delete avatar %s: %v", oldAvatarKey, err)
+		}
+	}
+
+	response := userToDTO(user)
+	return &response, nil
+}
+
+// uploadAvatar уменьшает изображение и перекодирует его в JPEG, отбрасывая метаданные
+func (s *ProfileService) uploadAvatar(ctx context.Context, userID uint, file *multipart.FileHeader) (string, string, error) {
+	if file.Size > s.maxAvatarSize {
+		return "", "", fmt.Errorf("avatar exceeds the %d MB limit", s.maxAvatarSize>>20)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", "", err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, s.maxAvatarSize+1))
+	if err != nil {
+		return "", "", err
+	}
+	if int64(len(data)) > s.maxAvatarSize {
+		return "", "", fmt.Errorf("avatar exceeds the %d MB limit", s.maxAvatarSize>>20)
+	}
+
+	contentType := http.DetectContentType(data)
+	if !avatarTypes[contentType] {
+		return "", "", fmt.Errorf("avatar has unsupported type %s", contentType)
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return "", "", errors.New("failed to decode avatar image")
+	}
+
+	var buf bytes.Buffer
+	if err := imaging.EncodeJPEG(&buf, imaging.Resize(img, imaging.Avatar.MaxSize)); err != nil {
+		return "", "", err
+	}
+
+	key, err := avatarKey(userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	url, err := s.storage.Upload(ctx, key, &buf, int64(buf.Len()), "image/jpeg")
+	if err != nil {
+		return "", "", err
+	}
+	return url, key, nil
+}
+
+// avatarKey новый ключ при каждой загрузке, чтобы не упираться в кеш браузера и CDN
+func avatarKey(userID uint) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("avatars/%d/%s.jpg", userID, hex.EncodeToString(buf)), nil
+}
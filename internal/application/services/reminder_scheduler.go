@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+)
+
+// reminderBatchSize сколько напоминаний выбирается за один запрос
+const reminderBatchSize = 200
+
+// ReminderScheduler в фоне напоминает участникам о мероприятиях за заданное время до начала.
+// Отправленные напоминания хранятся в БД, поэтому перезапуск не приводит к повторам
+type ReminderScheduler struct {
+	reminderRepo appInterfaces.ReminderRepository
+	offsets      []time.Duration
+	interval     time.Duration
+}
+
+func NewReminderScheduler(reminderRepo appInterfaces.ReminderRepository, offsets []time.Duration, interval time.Duration) *ReminderScheduler {
+	sorted := make([]time.Duration, 0, len(offsets))
+	for _, offset := range offsets {
+		if offset >= time.Minute {
+			sorted = append(sorted, offset)
+		}
+	}
+	// От большего смещения к меньшему: так у каждого смещения свое окно
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+
+	return &ReminderScheduler{
+		reminderRepo: reminderRepo,
+		offsets:      sorted,
+		interval:     interval,
+	}
+}
+
+// Start запускает периодическую проверку; без смещений планировщик выключен
+func (s *ReminderScheduler) Start(ctx context.Context) {
+	if len(s.offsets) == 0 || s.interval <= 0 {
+		log.Println("Reminder scheduler is disabled")
+		return
+	}
+	go s.run(ctx)
+}
+
+func (s *ReminderScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick отправляет напоминания, время которых наступило. Окно смещения заканчивается
+// там, где начинается окно следующего: кто записался за 30 минут до начала,
+// получит только ближайшее напоминание, а не все пропущенные сразу
+func (s *ReminderScheduler) tick(ctx context.Context) {
+	now := time.Now()
+	for i, offset := range s.offsets {
+		from := now
+		if i+1 < len(s.offsets) {
+			from = now.Add(s.offsets[i+1])
+		}
+		if err := s.sendDue(ctx, offset, from, now.Add(offset)); err != nil {
+			log.Printf("Reminder scheduler: offset %s: %v", offset, err)
+		}
+	}
+}
+
+func (s *ReminderScheduler) sendDue(ctx context.Context, offset time.Duration, from, to time.Time) error {
+	offsetMinutes := int(offset / time.Minute)
+	for {
+		due, err := s.reminderRepo.FindDue(ctx, offsetMinutes, from, to, reminderBatchSize)
+		if err != nil {
+			return err
+		}
+
+		sent := 0
+		now := time.Now()
+		for _, d := range due {
+			reminder := &entities.EventReminder{
+				EventID:       d.EventID,
+				UserID:        d.UserID,
+				OffsetMinutes: offsetMinutes,
+				SentAt:        time.Now(),
+			}
+			// Время до начала считаем по факту: после простоя напоминание за сутки
+			// может уйти, когда до мероприятия осталось несколько часов
+			notification := &entities.Notification{
+				UserID:    d.UserID,
+				Message:   fmt.Sprintf("Напоминание: мероприятие %s начнется через %s", d.Title, formatTimeLeft(d.EventDate.Sub(now))),
+				Type:      "event_reminder",
+				Read:      false,
+				CreatedAt: time.Now(),
+			}
+			ok, err := s.reminderRepo.Send(ctx, reminder, notification)
+			if err != nil {
+				log.Printf("Reminder scheduler: event %d, user %d: %v", d.EventID, d.UserID, err)
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		// Неполная пачка - больше ничего не осталось; пачка без отправленных - не крутимся на ошибках
+		if len(due) < reminderBatchSize || sent == 0 {
+			return nil
+		}
+	}
+}
+
+// formatTimeLeft округляет оставшееся время до самой крупной подходящей единицы
+func formatTimeLeft(left time.Duration) string {
+	minutes := left.Round(time.Minute)
+	switch {
+	case minutes >= 23*time.Hour+30*time.Minute:
+		return fmt.Sprintf("%d дн.", left.Round(24*time.Hour)/(24*time.Hour))
+	case minutes >= time.Hour:
+		return fmt.Sprintf("%d ч", left.Round(time.Hour)/time.Hour)
+	default:
+		return fmt.Sprintf("%d мин", max(minutes/time.Minute, 1))
+	}
+}
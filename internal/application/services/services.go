@@ -3,8 +3,14 @@ package services
 // Services объединяет все сервисы
 type Services struct {
 	Auth         *AuthService
+	TwoFactor    *TwoFactorService
+	OAuth        *OAuthService
+	Profile      *ProfileService
 	Event        *EventService
 	Comment      *CommentService
+	Media        *MediaService
 	Notification *NotificationService
 	Admin        *AdminService
+	Organizer    *OrganizerService
+	Access       *AccessService
 }
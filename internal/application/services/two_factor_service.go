@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth-system/internal/application/dto"
+	appInterfaces "auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+	"auth-system/internal/pkg/utils"
+)
+
+const recoveryCodeCount = 10
+
+// TwoFactorService подключение TOTP и коды восстановления
+type TwoFactorService struct {
+	userRepo      appInterfaces.UserRepository
+	recoveryRepo  appInterfaces.RecoveryCodeRepository
+	passwordUtil  utils.PasswordUtil
+	accessChecker appInterfaces.AccessChecker
+	loginGuard    *LoginGuard
+	issuer        string
+}
+
+func NewTwoFactorService(
+	userRepo appInterfaces.UserRepository,
+	recoveryRepo appInterfaces.RecoveryCodeRepository,
+	passwordUtil utils.PasswordUtil,
+	accessChecker appInterfaces.AccessChecker,
+	loginGuard *LoginGuard,
+	issuer string,
+) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:      userRepo,
+		recoveryRepo:  recoveryRepo,
+		passwordUtil:  passwordUtil,
+		accessChecker: accessChecker,
+		loginGuard:    loginGuard,
+		issuer:        issuer,
+	}
+}
+
+func (s *TwoFactorService) GetStatus(ctx context.Context, userID uint) (*dto.TwoFactorStatusResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	left, err := s.recoveryRepo.CountUnused(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorStatusResponse{
+		Enabled:           user.TOTPEnabled,
+		Required:          entities.RequiresTwoFactor(user.Role),
+		RecoveryCodesLeft: left,
+	}, nil
+}
+
+// Setup выпускает новый секрет; второй фактор включается только после подтверждения кодом
+func (s *TwoFactorService) Setup(ctx context.Context, userID uint) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateTwoFactor(ctx, userID, secret, false); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Enable подтверждает секрет кодом из приложения и выдает коды восстановления
+func (s *TwoFactorService) Enable(ctx context.Context, userID uint, req dto.TwoFactorCodeRequest) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor setup is not started")
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, req.Code, time.Now())
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	if err := s.userRepo.UpdateTwoFactor(ctx, userID, user.TOTPSecret, true); err != nil {
+		return nil, err
+	}
+	// Код подтверждения тоже нельзя повторно использовать для входа
+	s.userRepo.ConsumeTOTPStep(ctx, userID, step)
+	s.accessChecker.Invalidate(userID)
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// Disable выключает второй фактор; для ролей, где он обязателен, это запрещено.
+// Подбор пароля и кода ограничивается теми же счетчиками, что и вход
+func (s *TwoFactorService) Disable(ctx context.Context, userID uint, req dto.DisableTwoFactorRequest, clientIP string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if !user.TOTPEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+	if entities.RequiresTwoFactor(user.Role) {
+		return errors.New("two-factor authentication is required for your role")
+	}
+
+	attemptID, err := s.loginGuard.Begin(ctx, user.Email, clientIP, user)
+	if err != nil {
+		return err
+	}
+
+	if !s.passwordUtil.CheckPasswordHash(req.Password, user.PasswordHash) {
+		s.loginGuard.Fail(ctx, attemptID, user.Email, user, entities.LoginReasonInvalidCredentials)
+		return errors.New("invalid password")
+	}
+
+	ok, err := s.VerifyCode(ctx, user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.loginGuard.Fail(ctx, attemptID, user.Email, user, entities.LoginReasonInvalidOTP)
+		return errors.New("invalid two-factor code")
+	}
+	s.loginGuard.Confirm(ctx, attemptID)
+
+	if err := s.userRepo.UpdateTwoFactor(ctx, userID, "", false); err != nil {
+		return err
+	}
+	s.accessChecker.Invalidate(userID)
+
+	return s.recoveryRepo.DeleteForUser(ctx, userID)
+}
+
+// RegenerateRecoveryCodes заменяет коды восстановления; старые перестают действовать
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint, req dto.TwoFactorCodeRequest, clientIP string) (*dto.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if !user.TOTPEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+
+	attemptID, err := s.loginGuard.Begin(ctx, user.Email, clientIP, user)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.verifyTOTP(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.loginGuard.Fail(ctx, attemptID, user.Email, user, entities.LoginReasonInvalidOTP)
+		return nil, errors.New("invalid two-factor code")
+	}
+	s.loginGuard.Confirm(ctx, attemptID)
+
+	return s.issueRecoveryCodes(ctx, userID)
+}
+
+// VerifyCode принимает код из приложения или одноразовый код восстановления
+func (s *TwoFactorService) VerifyCode(ctx context.Context, user *entities.User, code string) (bool, error) {
+	if ok, err := s.verifyTOTP(ctx, user, code); ok || err != nil {
+		return ok, err
+	}
+	return s.recoveryRepo.Use(ctx, user.ID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+}
+
+// verifyTOTP проверяет код и не дает принять один и тот же код дважды
+func (s *TwoFactorService) verifyTOTP(ctx context.Context, user *entities.User, code string) (bool, error) {
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	return s.userRepo.ConsumeTOTPStep(ctx, user.ID, step)
+}
+
+func (s *TwoFactorService) issueRecoveryCodes(ctx context.Context, userID uint) (*dto.RecoveryCodesResponse, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = utils.HashToken(utils.NormalizeRecoveryCode(code))
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
@@ -2,12 +2,86 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
 	DatabaseURL string
 	ServerPort  string
 	JWTSecret   string
+	// Адреса/подсети прокси, которым разрешено передавать IP клиента в X-Forwarded-For.
+	// По умолчанию пусто: IP берется из соединения, и заголовок нельзя подделать
+	TrustedProxies []string
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// Как долго статус пользователя (блокировка, роль) берется из кеша
+	UserStatusCacheTTL time.Duration
+
+	// Хранилище медиафайлов: "local" или "s3". Исходники фотографий до обработки
+	// лежат отдельно (PrivateDir или S3PrivateBucket) и наружу не раздаются
+	StorageDriver   string
+	UploadDir       string
+	UploadURL       string
+	PrivateDir      string
+	S3Endpoint      string
+	S3AccessKey     string
+	S3SecretKey     string
+	S3Bucket        string
+	S3PrivateBucket string
+	S3PublicURL     string
+	S3UseSSL        bool
+	MaxUploadSizeMB int64
+	MaxBodySizeMB   int64
+	MediaWorkers    int
+	MaxAvatarSizeMB int64
+
+	// Почта: "smtp", "file" (письма в MAIL_DIR) или "memory"
+	MailDriver   string
+	MailDir      string
+	MailFrom     string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// Адрес фронтенда для ссылок в письмах
+	AppURL                string
+	EmailVerificationTTL  time.Duration
+	PasswordResetTokenTTL time.Duration
+
+	// Защита от перебора паролей
+	LoginBackoffThreshold   int
+	LoginBackoffBase        time.Duration
+	LoginBackoffMax         time.Duration
+	LoginLockThreshold      int
+	LoginLockDuration       time.Duration
+	LoginIPBackoffThreshold int
+	LoginFailureWindow      time.Duration
+
+	// Двухфакторная аутентификация
+	TOTPIssuer        string
+	LoginChallengeTTL time.Duration
+
+	// Вход через внешних провайдеров; провайдер подключается, если задан его client id.
+	// Адрес возврата: OAUTH_REDIRECT_URL/<provider>
+	OAuthRedirectURL   string
+	GoogleClientID     string
+	GoogleClientSecret string
+	YandexClientID     string
+	YandexClientSecret string
+	VKClientID         string
+	VKClientSecret     string
+	// Произвольный OIDC-провайдер по discovery, например локальный mock
+	OIDCProviderName string
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+
+	// Напоминания участникам: за сколько до начала (через запятую) и как часто проверять
+	ReminderOffsets  []time.Duration
+	ReminderInterval time.Duration
 }
 
 func Load() *Config {
@@ -15,6 +89,65 @@ func Load() *Config {
 		DatabaseURL: getEnv("DATABASE_URL", "host=localhost user=max password=123456 dbname=kurs port=5432 sslmode=disable"),
 		ServerPort:  getEnv("SERVER_PORT", ":8080"),
 		JWTSecret:   getEnv("JWT_SECRET", "BpR0cOjcNNiskIZu9ZtS3Q3o3M2RzNEEAQIZVJFX5uC"),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		AccessTokenTTL:     getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:    getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		UserStatusCacheTTL: getEnvDuration("USER_STATUS_CACHE_TTL", 30*time.Second),
+
+		StorageDriver:   getEnv("STORAGE_DRIVER", "local"),
+		UploadDir:       getEnv("UPLOAD_DIR", "./uploads"),
+		UploadURL:       getEnv("UPLOAD_URL", "http://localhost:8080/uploads"),
+		PrivateDir:      getEnv("PRIVATE_UPLOAD_DIR", "./uploads-private"),
+		S3Endpoint:      getEnv("S3_ENDPOINT", "localhost:9000"),
+		S3AccessKey:     getEnv("S3_ACCESS_KEY", "minioadmin"),
+		S3SecretKey:     getEnv("S3_SECRET_KEY", "minioadmin"),
+		S3Bucket:        getEnv("S3_BUCKET", "events"),
+		S3PrivateBucket: getEnv("S3_PRIVATE_BUCKET", "events-originals"),
+		S3PublicURL:     getEnv("S3_PUBLIC_URL", ""),
+		S3UseSSL:        getEnvBool("S3_USE_SSL", false),
+		MaxUploadSizeMB: getEnvInt("MAX_UPLOAD_SIZE_MB", 10),
+		MaxBodySizeMB:   getEnvInt("MAX_BODY_SIZE_MB", 100),
+		MediaWorkers:    int(getEnvInt("MEDIA_WORKERS", 2)),
+		MaxAvatarSizeMB: getEnvInt("MAX_AVATAR_SIZE_MB", 5),
+
+		MailDriver:            getEnv("MAIL_DRIVER", "file"),
+		MailDir:               getEnv("MAIL_DIR", "./mail"),
+		MailFrom:              getEnv("MAIL_FROM", "no-reply@localhost"),
+		SMTPHost:              getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:              int(getEnvInt("SMTP_PORT", 587)),
+		SMTPUsername:          getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		AppURL:                getEnv("APP_URL", "http://localhost:3000"),
+		EmailVerificationTTL:  getEnvDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+		PasswordResetTokenTTL: getEnvDuration("PASSWORD_RESET_TOKEN_TTL", time.Hour),
+
+		LoginBackoffThreshold:   int(getEnvInt("LOGIN_BACKOFF_THRESHOLD", 3)),
+		LoginBackoffBase:        getEnvDuration("LOGIN_BACKOFF_BASE", time.Second),
+		LoginBackoffMax:         getEnvDuration("LOGIN_BACKOFF_MAX", 5*time.Minute),
+		LoginLockThreshold:      int(getEnvInt("LOGIN_LOCK_THRESHOLD", 10)),
+		LoginLockDuration:       getEnvDuration("LOGIN_LOCK_DURATION", 15*time.Minute),
+		LoginIPBackoffThreshold: int(getEnvInt("LOGIN_IP_BACKOFF_THRESHOLD", 20)),
+		LoginFailureWindow:      getEnvDuration("LOGIN_FAILURE_WINDOW", time.Hour),
+
+		TOTPIssuer:        getEnv("TOTP_ISSUER", "Events"),
+		LoginChallengeTTL: getEnvDuration("LOGIN_CHALLENGE_TTL", 5*time.Minute),
+
+		OAuthRedirectURL:   getEnv("OAUTH_REDIRECT_URL", "http://localhost:3000/oauth/callback"),
+		GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
+		YandexClientID:     getEnv("YANDEX_CLIENT_ID", ""),
+		YandexClientSecret: getEnv("YANDEX_CLIENT_SECRET", ""),
+		VKClientID:         getEnv("VK_CLIENT_ID", ""),
+		VKClientSecret:     getEnv("VK_CLIENT_SECRET", ""),
+		OIDCProviderName:   getEnv("OIDC_PROVIDER_NAME", "oidc"),
+		OIDCIssuer:         getEnv("OIDC_ISSUER", ""),
+		OIDCClientID:       getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:   getEnv("OIDC_CLIENT_SECRET", ""),
+
+		ReminderOffsets:  getEnvDurations("REMINDER_OFFSETS", []time.Duration{24 * time.Hour, time.Hour}),
+		ReminderInterval: getEnvDuration("REMINDER_INTERVAL", time.Minute),
 	}
 }
 
@@ -25,3 +158,59 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvInt(key string, defaultValue int64) int64 {
+	value, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvList разбирает список через запятую; пустые элементы пропускаются
+func getEnvList(key string) []string {
+	var values []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// getEnvDurations разбирает список вроде "24h,1h"; "off" отключает значение по умолчанию
+func getEnvDurations(key string, defaultValue []time.Duration) []time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if value == "off" {
+		return nil
+	}
+
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		duration, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return defaultValue
+		}
+		durations = append(durations, duration)
+	}
+	return durations
+}
@@ -1,6 +1,12 @@
 package entities
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrCommentNotFound комментарий не существует
+var ErrCommentNotFound = errors.New("comment not found")
 
 type Comment struct {
 	ID        uint      `json:"id"`
@@ -9,9 +15,16 @@ type Comment struct {
 	UserID    uint      `json:"user_id"`
 	ParentID  *uint     `json:"parent_id"`
 	Score     int       `json:"score"`
+	Upvotes   int       `json:"upvotes"`
+	Downvotes int       `json:"downvotes"`
 	IsDeleted bool      `json:"is_deleted"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	User      User      `json:"user" gorm:"-"`
-	Replies   []Comment `json:"replies" gorm:"-"`
+	// Depth и ReplyCount заполняются при выборке дерева комментариев
+	Depth      int `json:"depth" gorm:"->"`
+	ReplyCount int `json:"reply_count" gorm:"->"`
+	// EventTitle заполняется в выборке комментариев пользователя
+	EventTitle string    `json:"event_title" gorm:"->"`
+	User       User      `json:"user" gorm:"-"`
+	Replies    []Comment `json:"replies" gorm:"-"`
 }
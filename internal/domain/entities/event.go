@@ -15,17 +15,35 @@ type Event struct {
 	Address           string             `json:"address"`
 	IsVerified        bool               `json:"is_verified"`
 	IsActive          bool               `json:"is_active"`
+	CancelledAt       *time.Time         `json:"cancelled_at" gorm:"->"`
+	CancelReason      string             `json:"cancellation_reason" gorm:"->"`
 	CreatorID         uint               `json:"creator_id"`
 	Creator           User               `json:"creator"`
 	CreatedAt         time.Time          `json:"created_at"`
 	UpdatedAt         time.Time          `json:"updated_at"`
 	ParticipantsCount int                `json:"participants_count"`
+	RSVPCounts        map[string]int     `json:"rsvp_counts" gorm:"-"`
+	DistanceKm        *float64           `json:"distance_km,omitempty" gorm:"->"`
 	Tags              []Tag              `json:"tags" gorm:"-"`
 	Media             []EventMedia       `json:"media" gorm:"-"`
 	Participants      []EventParticipant `json:"participants" gorm:"-"`
 	Comments          []Comment          `json:"comments" gorm:"-"`
 }
 
+// SetRSVPCounts заполняет счетчики по статусам; ParticipantsCount - число идущих
+func (e *Event) SetRSVPCounts(counts map[string]int) {
+	e.RSVPCounts = make(map[string]int, len(ParticipantStatuses))
+	for _, status := range ParticipantStatuses {
+		e.RSVPCounts[status] = counts[status]
+	}
+	e.ParticipantsCount = counts[ParticipantStatusGoing]
+}
+
+// IsCancelled: отмененное мероприятие остается видимым, но запись на него закрыта
+func (e *Event) IsCancelled() bool {
+	return e.CancelledAt != nil
+}
+
 func (e *Event) IsFull() bool {
 	if e.MaxParticipants == nil {
 		return false
@@ -0,0 +1,31 @@
+package entities
+
+// BoundingBox описывает видимую область карты в координатах WGS84
+type BoundingBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// GeoPoint точка на карте, относительно которой ищутся мероприятия
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// EventCluster группа близко расположенных мероприятий для заданного масштаба
+type EventCluster struct {
+	Count     int
+	Latitude  float64
+	Longitude float64
+	MinLat    float64
+	MinLon    float64
+	MaxLat    float64
+	MaxLon    float64
+	EventID   uint
+}
+
+func (c *EventCluster) IsSingle() bool {
+	return c.Count == 1
+}
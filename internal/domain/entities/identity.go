@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// UserIdentity аккаунт внешнего провайдера, привязанный к пользователю
+type UserIdentity struct {
+	ID          uint       `json:"id"`
+	UserID      uint       `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// OAuthState состояние начатого входа через провайдера: защищает от CSRF (state)
+// и хранит code_verifier для PKCE. UserID задан, когда провайдер привязывают к аккаунту.
+type OAuthState struct {
+	ID           uint      `json:"id"`
+	StateHash    string    `json:"-"`
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"-"`
+	UserID       *uint     `json:"user_id"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (s *OAuthState) IsExpired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+func (OAuthState) TableName() string {
+	return "oauth_states"
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// Причины записи в журнале входов
+const (
+	LoginReasonSuccess            = "success"
+	LoginReasonInvalidCredentials = "invalid_credentials"
+	LoginReasonInvalidOTP         = "invalid_otp"
+	LoginReasonThrottled          = "throttled"
+	LoginReasonLocked             = "locked"
+	LoginReasonBlocked            = "blocked"
+	// Пароль верный, вход ждет второго фактора
+	LoginReasonSecondFactor = "second_factor"
+	// Повторная проверка пароля или кода перед важным действием прошла успешно
+	LoginReasonConfirmed = "confirmed"
+	// Попытка начата, но еще не завершена; до завершения считается неудачной
+	LoginReasonPending = "pending"
+)
+
+// LoginFailures неудачи по аккаунту и по IP, накопленные к началу попытки
+type LoginFailures struct {
+	Account     int64
+	AccountLast time.Time
+	IP          int64
+	IPLast      time.Time
+}
+
+// LoginAttempt запись журнала попыток входа
+type LoginAttempt struct {
+	ID        uint      `json:"id"`
+	UserID    *uint     `json:"user_id"`
+	Email     string    `json:"email"`
+	IP        string    `json:"ip" gorm:"column:ip"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginActivity сводка неудачных входов с одного IP
+type LoginActivity struct {
+	IP          string    `json:"ip" gorm:"column:ip"`
+	Failures    int64     `json:"failures"`
+	Accounts    int64     `json:"accounts"`
+	LastAttempt time.Time `json:"last_attempt"`
+}
+
+// LoginThrottledError вход временно запрещен: слишком много неудачных попыток
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("account is temporarily locked, retry in %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// RecoveryCode одноразовый код восстановления доступа при потере второго фактора
+type RecoveryCode struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -1,6 +1,10 @@
 package entities
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+)
 
 type Tag struct {
 	ID        uint      `json:"id"`
@@ -15,22 +19,105 @@ type EventTag struct {
 }
 
 type EventMedia struct {
-	ID         uint      `json:"id"`
-	EventID    uint      `json:"event_id"`
-	FileURL    string    `json:"file_url"`
-	FileType   string    `json:"file_type"`
-	OrderIndex int       `json:"order_index"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID         uint   `json:"id"`
+	EventID    uint   `json:"event_id"`
+	FileURL    string `json:"file_url"`
+	FileType   string `json:"file_type"`
+	StorageKey string `json:"-"`
+	// Уменьшенные копии фотографии, заполняются фоновой обработкой
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CardURL      string    `json:"card_url"`
+	Status       string    `json:"status"`
+	OrderIndex   int       `json:"order_index"`
+	UploadedAt   time.Time `json:"uploaded_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Статусы обработки медиафайлов
+const (
+	MediaStatusProcessing = "processing"
+	MediaStatusReady      = "ready"
+	MediaStatusFailed     = "failed"
+)
+
+func (m *EventMedia) IsImage() bool {
+	return strings.HasPrefix(m.FileType, "image/")
+}
+
+// Статусы участия. Место занимает только going; в очередь (waitlisted) попадают,
+// когда мест на мероприятии не осталось
+const (
+	ParticipantStatusGoing      = "going"
+	ParticipantStatusWaitlisted = "waitlisted"
+	ParticipantStatusInterested = "interested"
+	ParticipantStatusMaybe      = "maybe"
+	ParticipantStatusDeclined   = "declined"
+)
+
+// ParticipantStatuses все статусы в порядке вывода счетчиков
+var ParticipantStatuses = []string{
+	ParticipantStatusGoing,
+	ParticipantStatusWaitlisted,
+	ParticipantStatusInterested,
+	ParticipantStatusMaybe,
+	ParticipantStatusDeclined,
+}
+
+// Ошибки записи на мероприятие
+var (
+	ErrEventFull            = errors.New("event is full")
+	ErrAlreadyParticipating = errors.New("already participating")
+	ErrAlreadyWaitlisted    = errors.New("already on the waitlist")
+	ErrNotParticipating     = errors.New("not participating")
+	ErrEventCancelled       = errors.New("event is cancelled")
+)
+
+// Ошибки доступа к мероприятию и его файлам; контроллеры отвечают на них 404 и 403
+var (
+	ErrEventNotFound = errors.New("event not found")
+	ErrMediaNotFound = errors.New("media not found")
+	ErrNotAuthorized = errors.New("not authorized")
+)
+
+// MediaValidationError файлы или порядок галереи отклонены проверкой; контроллер отвечает 400
+type MediaValidationError struct {
+	Reason string
+}
+
+func (e *MediaValidationError) Error() string {
+	return e.Reason
 }
 
 type EventParticipant struct {
-	EventID  uint      `json:"event_id"`
-	UserID   uint      `json:"user_id"`
-	Status   string    `json:"status"`
-	JoinedAt time.Time `json:"joined_at"`
-	User     User      `json:"user"`
-	Event    Event     `json:"event"`
+	EventID        uint      `json:"event_id"`
+	UserID         uint      `json:"user_id"`
+	Status         string    `json:"status"`
+	JoinedAt       time.Time `json:"joined_at"`
+	RemindersMuted bool      `json:"reminders_muted"`
+	User           User      `json:"user"`
+	Event          Event     `json:"event"`
 }
+
+func (p *EventParticipant) IsWaitlisted() bool {
+	return p.Status == ParticipantStatusWaitlisted
+}
+
+// EventReminder отметка об отправленном напоминании, уникальна для (мероприятие, пользователь, смещение)
+type EventReminder struct {
+	EventID       uint      `json:"event_id"`
+	UserID        uint      `json:"user_id"`
+	OffsetMinutes int       `json:"offset_minutes"`
+	SentAt        time.Time `json:"sent_at"`
+}
+
+// DueReminder участник, которому пора напомнить о мероприятии
+type DueReminder struct {
+	EventID   uint
+	UserID    uint
+	Title     string
+	EventDate time.Time
+}
+
 type CommentVote struct {
 	UserID    uint      `json:"user_id"`
 	CommentID uint      `json:"comment_id"`
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Статусы заявки на статус проверенного организатора
+const (
+	ApplicationStatusPending  = "pending"
+	ApplicationStatusApproved = "approved"
+	ApplicationStatusRejected = "rejected"
+)
+
+// OrganizerApplication заявка пользователя на статус проверенного организатора
+type OrganizerApplication struct {
+	ID         uint       `json:"id"`
+	UserID     uint       `json:"user_id"`
+	Message    string     `json:"message"`
+	Status     string     `json:"status"`
+	ReviewedBy *uint      `json:"reviewed_by"`
+	ReviewNote string     `json:"review_note"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	User       User       `json:"user" gorm:"-"`
+}
+
+func (a *OrganizerApplication) IsPending() bool {
+	return a.Status == ApplicationStatusPending
+}
@@ -0,0 +1,87 @@
+package entities
+
+// Permission право на отдельное действие в системе
+type Permission string
+
+const (
+	PermAdminPanel       Permission = "admin.access"
+	PermEventsAutoVerify Permission = "events.auto_verify"
+	PermEventsViewAll    Permission = "events.view_all"
+	PermEventsVerify     Permission = "events.verify"
+	PermEventsDelete     Permission = "events.delete"
+	PermCommentsDelete   Permission = "comments.delete"
+	PermUsersView        Permission = "users.view"
+	PermUsersBlock       Permission = "users.block"
+	PermUsersManageRoles Permission = "users.roles"
+	PermOrganizersVerify Permission = "organizers.verify"
+	PermStatisticsView   Permission = "statistics.view"
+)
+
+// roleLevels старшинство ролей: управлять можно только ролями ниже своей
+var roleLevels = map[string]int{
+	RoleUser:       0,
+	RoleOrganizer:  1,
+	RoleModerator:  2,
+	RoleAdmin:      3,
+	RoleSuperAdmin: 4,
+}
+
+var moderatorPermissions = []Permission{
+	PermAdminPanel,
+	PermEventsViewAll,
+	PermEventsVerify,
+	PermEventsDelete,
+	PermCommentsDelete,
+}
+
+var adminPermissions = append([]Permission{
+	PermEventsAutoVerify,
+	PermUsersView,
+	PermUsersBlock,
+	PermUsersManageRoles,
+	PermOrganizersVerify,
+	PermStatisticsView,
+}, moderatorPermissions...)
+
+// Организатору публикация без модерации дает не роль, а отметка о проверке (User.IsVerifiedOrganizer)
+var rolePermissions = map[string][]Permission{
+	RoleUser:       {},
+	RoleOrganizer:  {},
+	RoleModerator:  moderatorPermissions,
+	RoleAdmin:      adminPermissions,
+	RoleSuperAdmin: adminPermissions,
+}
+
+// HasPermission проверяет, входит ли право в набор роли
+func HasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesWithPermission возвращает все роли, которым выдано право
+func RolesWithPermission(permission Permission) []string {
+	var roles []string
+	for role := range rolePermissions {
+		if HasPermission(role, permission) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+func IsValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// CanManageRole разрешает менять роль target только старшей роли; суперадмин управляет всеми
+func CanManageRole(actorRole, targetRole string) bool {
+	if actorRole == RoleSuperAdmin {
+		return true
+	}
+	return roleLevels[actorRole] > roleLevels[targetRole]
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// RefreshToken долгоживущий токен обновления; все токены одной сессии
+// объединены FamilyID, чтобы при повторном использовании отозвать всю цепочку
+type RefreshToken struct {
+	ID         uint       `json:"id"`
+	UserID     uint       `json:"user_id"`
+	TokenHash  string     `json:"-"`
+	FamilyID   string     `json:"family_id"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	ReplacedBy *uint      `json:"replaced_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
@@ -5,20 +5,73 @@ import (
 )
 
 type User struct {
-	ID           uint      `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	Role         string    `json:"role"`
-	AvatarURL    string    `json:"avatar_url"`
-	IsBlocked    bool      `json:"is_blocked"`
-	LastOnline   time.Time `json:"last_online"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                uint       `json:"id"`
+	Username          string     `json:"username"`
+	Email             string     `json:"email"`
+	PasswordHash      string     `json:"-"`
+	Role              string     `json:"role"`
+	AvatarURL         string     `json:"avatar_url"`
+	AvatarKey         string     `json:"-"`
+	Bio               string     `json:"bio"`
+	HideParticipation bool       `json:"hide_participation"`
+	OrganizerVerified *time.Time `json:"organizer_verified_at" gorm:"column:organizer_verified_at;->"`
+	IsBlocked         bool       `json:"is_blocked"`
+	EmailVerified     bool       `json:"email_verified"`
+	LockedUntil       *time.Time `json:"locked_until"`
+	TOTPSecret        string     `json:"-" gorm:"column:totp_secret"`
+	TOTPEnabled       bool       `json:"totp_enabled" gorm:"column:totp_enabled"`
+	TOTPLastStep      int64      `json:"-" gorm:"column:totp_last_step;->"`
+	TokenVersion      int        `json:"-" gorm:"->"`
+	LastOnline        time.Time  `json:"last_online"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
+// UserStatus данные аккаунта, проверяемые при каждом запросе
+type UserStatus struct {
+	UserID       uint
+	Role         string
+	IsBlocked    bool
+	TokenVersion int
+	TOTPEnabled  bool `gorm:"column:totp_enabled"`
+}
+
+// Роли пользователей
+const (
+	RoleUser       = "user"
+	RoleOrganizer  = "organizer"
+	RoleModerator  = "moderator"
+	RoleAdmin      = "admin"
+	RoleSuperAdmin = "superadmin"
+)
+
 func (u *User) IsAdmin() bool {
-	return u.Role == "admin"
+	return u.Role == RoleAdmin || u.Role == RoleSuperAdmin
+}
+
+func (u *User) Can(permission Permission) bool {
+	return HasPermission(u.Role, permission)
+}
+
+// IsVerifiedOrganizer отмечает пользователей, чья заявка организатора одобрена администратором.
+// Проверка хранится отдельно от роли: ее может пройти и сотрудник
+func (u *User) IsVerifiedOrganizer() bool {
+	return u.OrganizerVerified != nil
+}
+
+// PublishesWithoutReview мероприятия проверенных организаторов и сотрудников не ждут модерации
+func (u *User) PublishesWithoutReview() bool {
+	return u.IsVerifiedOrganizer() || u.Can(PermEventsAutoVerify)
+}
+
+// IsLocked сообщает о временной блокировке после неудачных попыток входа
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
+}
+
+// RequiresTwoFactor роли с широкими правами обязаны подключить TOTP
+func RequiresTwoFactor(role string) bool {
+	return role == RoleAdmin || role == RoleSuperAdmin
 }
 
 func (u *User) IsActive() bool {
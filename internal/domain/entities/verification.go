@@ -0,0 +1,33 @@
+package entities
+
+import "time"
+
+// Назначения одноразовых токенов, отправляемых по почте
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	// Смена адреса: Email токена - новый адрес, который еще предстоит подтвердить
+	TokenPurposeEmailChange = "email_change"
+	// Промежуточный токен входа: пароль проверен, ожидается код второго фактора
+	TokenPurposeLoginChallenge = "login_challenge"
+)
+
+// VerificationToken одноразовый токен из письма; в БД хранится только хеш
+type VerificationToken struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	TokenHash string     `json:"-"`
+	Purpose   string     `json:"purpose"`
+	Email     string     `json:"email"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (t *VerificationToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+func (t *VerificationToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
@@ -4,12 +4,14 @@ import (
 	"net/http"
 	"strings"
 
+	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
 	"auth-system/internal/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtUtil utils.JWTUtil) gin.HandlerFunc {
+func AuthMiddleware(jwtUtil utils.JWTUtil, accessChecker interfaces.AccessChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -26,8 +28,47 @@ func AuthMiddleware(jwtUtil utils.JWTUtil) gin.HandlerFunc {
 			return
 		}
 
+		// Подпись валидна, но аккаунт мог быть заблокирован после выдачи токена
+		status, err := accessChecker.CheckAccess(c.Request.Context(), claims.UserID, claims.TokenVersion)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("user_role", status.Role)
+		c.Set("user_email", claims.Email)
+		c.Set("user_totp_enabled", status.TOTPEnabled)
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware пропускает гостей, но распознает пользователя при наличии валидного токена
+func OptionalAuthMiddleware(jwtUtil utils.JWTUtil, accessChecker interfaces.AccessChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
+			return
+		}
+
+		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
+		claims, err := jwtUtil.ParseToken(tokenString)
+		if err != nil {
+			// Просроченный или поддельный токен - продолжаем как гость
+			c.Next()
+			return
+		}
+
+		status, err := accessChecker.CheckAccess(c.Request.Context(), claims.UserID, claims.TokenVersion)
+		if err != nil {
+			c.Next()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
-		c.Set("user_role", claims.Role)
+		c.Set("user_role", status.Role)
 		c.Set("user_email", claims.Email)
 		c.Next()
 	}
@@ -53,3 +94,39 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 		c.Abort()
 	}
 }
+
+// PermissionMiddleware пропускает запрос, если роль пользователя дает все перечисленные права
+func PermissionMiddleware(permissions ...entities.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, exists := c.Get("user_role")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		for _, permission := range permissions {
+			if !entities.HasPermission(userRole.(string), permission) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// TwoFactorEnrolledMiddleware не пускает администраторов без подключенной 2FA
+func TwoFactorEnrolledMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole := c.GetString("user_role")
+		if entities.RequiresTwoFactor(userRole) && !c.GetBool("user_totp_enabled") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication enrolment required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,6 +1,8 @@
 package http
 
 import (
+	"log"
+
 	"auth-system/internal/config"
 
 	"github.com/gin-gonic/gin"
@@ -13,6 +15,11 @@ type Server struct {
 
 func NewServer(cfg *config.Config) *Server {
 	engine := gin.Default()
+	// Без доверенных прокси ClientIP не читает X-Forwarded-For, иначе клиент
+	// подменял бы свой IP и обходил ограничения попыток входа
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 	return &Server{
 		engine: engine,
 		config: cfg,
@@ -0,0 +1,31 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"auth-system/internal/application/interfaces"
+)
+
+// FileMailer складывает письма в папку в виде .eml файлов; для локальной разработки
+type FileMailer struct {
+	dir     string
+	from    string
+	counter atomic.Uint64
+}
+
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, message interfaces.MailMessage) error {
+	name := fmt.Sprintf("%s-%d.eml", time.Now().Format("20060102-150405"), m.counter.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, message), 0o644)
+}
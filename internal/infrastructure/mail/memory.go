@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"context"
+	"sync"
+
+	"auth-system/internal/application/interfaces"
+)
+
+// MemoryMailer хранит отправленные письма в памяти; используется в тестах
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []interfaces.MailMessage
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, message interfaces.MailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+// Messages возвращает копию всех отправленных писем
+func (m *MemoryMailer) Messages() []interfaces.MailMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]interfaces.MailMessage(nil), m.messages...)
+}
+
+// LastTo возвращает последнее письмо на адрес to
+func (m *MemoryMailer) LastTo(to string) (interfaces.MailMessage, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+	return interfaces.MailMessage{}, false
+}
+
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
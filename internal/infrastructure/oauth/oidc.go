@@ -0,0 +1,145 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"auth-system/internal/application/interfaces"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// userInfoClaims стандартные claims OIDC; email_verified у некоторых провайдеров строка
+type userInfoClaims struct {
+	Subject       string      `json:"sub"`
+	Email         string      `json:"email"`
+	EmailVerified interface{} `json:"email_verified"`
+	Name          string      `json:"name"`
+	Picture       string      `json:"picture"`
+}
+
+// idTokenClaims подписанные издателем claims; почта берется прежде всего отсюда
+type idTokenClaims struct {
+	Email           string      `json:"email"`
+	EmailVerified   interface{} `json:"email_verified"`
+	AuthorizedParty string      `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// NewOIDCProvider настраивает провайдер по документу discovery издателя.
+// Так подключаются Google и любой OIDC-совместимый сервер, в том числе локальный mock.
+func NewOIDCProvider(ctx context.Context, name, issuer, clientID, clientSecret, redirectURL string) (*Provider, error) {
+	p := &Provider{
+		name:         name,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       []string{"openid", "email", "profile"},
+		httpClient:   defaultHTTPClient(),
+	}
+
+	var discovery discoveryDocument
+	endpoint := strings.TrimRight(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.do(req, &discovery); err != nil {
+		return nil, err
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" ||
+		discovery.UserinfoEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("incomplete OIDC discovery document")
+	}
+	// Документ, подложенный по чужому адресу, не должен подменить издателя
+	if strings.TrimRight(discovery.Issuer, "/") != strings.TrimRight(issuer, "/") {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", discovery.Issuer, issuer)
+	}
+
+	p.authURL = discovery.AuthorizationEndpoint
+	p.tokenURL = discovery.TokenEndpoint
+	userinfoURL := discovery.UserinfoEndpoint
+	keys := newKeySet(discovery.JWKSURI, p.httpClient)
+
+	p.fetchProfile = func(ctx context.Context, p *Provider, token tokenResponse) (*interfaces.ExternalProfile, error) {
+		idToken, err := verifyIDToken(ctx, keys, token.IDToken, discovery.Issuer, p.clientID)
+		if err != nil {
+			return nil, err
+		}
+
+		var claims userInfoClaims
+		if err := p.getJSON(ctx, userinfoURL, "Bearer "+token.AccessToken, &claims); err != nil {
+			return nil, err
+		}
+		// userinfo должен описывать того же пользователя, что и id_token
+		if claims.Subject != idToken.Subject {
+			return nil, errors.New("userinfo subject does not match id_token")
+		}
+		if idToken.Email != "" {
+			claims.Email = idToken.Email
+			claims.EmailVerified = idToken.EmailVerified
+		}
+
+		return &interfaces.ExternalProfile{
+			Subject:       idToken.Subject,
+			Email:         claims.Email,
+			EmailVerified: isTrue(claims.EmailVerified),
+			Name:          claims.Name,
+			AvatarURL:     claims.Picture,
+		}, nil
+	}
+
+	return p, nil
+}
+
+// verifyIDToken проверяет подпись по ключам издателя, iss, aud и срок действия
+func verifyIDToken(ctx context.Context, keys *keySet, rawToken, issuer, clientID string) (*idTokenClaims, error) {
+	if rawToken == "" {
+		return nil, errors.New("missing id_token")
+	}
+
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return keys.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != clientID {
+		return nil, errors.New("invalid id_token: unexpected authorized party")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id_token: missing subject")
+	}
+	return claims, nil
+}
+
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
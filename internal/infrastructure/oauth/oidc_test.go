@@ -0,0 +1,235 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	mockClientID = "events-app"
+	mockKeyID    = "mock-key"
+)
+
+// mockOIDC локальный OIDC-провайдер: discovery, ключи, токен-эндпоинт и userinfo
+type mockOIDC struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	// idTokenClaims и userinfo отдаются при обмене кода; тест может их подменить
+	idTokenClaims jwt.MapClaims
+	userinfo      map[string]interface{}
+	// signingKey подписывает id_token; по умолчанию опубликованный ключ
+	signingKey *rsa.PrivateKey
+	noIDToken  bool
+}
+
+func newMockOIDC(t *testing.T) *mockOIDC {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockOIDC{key: key}
+	mux := http.NewServeMux()
+	discovery := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 m.server.URL,
+			"authorization_endpoint": m.server.URL + "/authorize",
+			"token_endpoint":         m.server.URL + "/token",
+			"userinfo_endpoint":      m.server.URL + "/userinfo",
+			"jwks_uri":               m.server.URL + "/jwks",
+		})
+	}
+	mux.HandleFunc("/.well-known/openid-configuration", discovery)
+	// Тот же документ по другому пути: издатель в нем не совпадет с запрошенным
+	mux.HandleFunc("/other/.well-known/openid-configuration", discovery)
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": mockKeyID,
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("code") != "good-code" || r.Form.Get("code_verifier") == "" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		response := map[string]string{"access_token": "mock-access-token", "token_type": "Bearer"}
+		if !m.noIDToken {
+			response["id_token"] = m.signIDToken(t)
+		}
+		writeJSON(w, response)
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer mock-access-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		writeJSON(w, m.userinfo)
+	})
+	m.server = httptest.NewServer(mux)
+	t.Cleanup(m.server.Close)
+
+	m.idTokenClaims = jwt.MapClaims{
+		"iss":            m.server.URL,
+		"aud":            mockClientID,
+		"sub":            "user-42",
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+	m.userinfo = map[string]interface{}{
+		"sub":     "user-42",
+		"email":   "alice@example.com",
+		"name":    "Alice",
+		"picture": "https://example.com/alice.png",
+	}
+	return m
+}
+
+func (m *mockOIDC) signIDToken(t *testing.T) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, m.idTokenClaims)
+	token.Header["kid"] = mockKeyID
+	key := m.signingKey
+	if key == nil {
+		key = m.key
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func TestOIDCProviderExchange(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		setup   func(m *mockOIDC)
+		wantErr string
+	}{
+		{
+			name: "valid id_token",
+		},
+		{
+			name:    "missing id_token",
+			setup:   func(m *mockOIDC) { m.noIDToken = true },
+			wantErr: "missing id_token",
+		},
+		{
+			name:    "wrong audience",
+			setup:   func(m *mockOIDC) { m.idTokenClaims["aud"] = "someone-else" },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "wrong issuer",
+			setup:   func(m *mockOIDC) { m.idTokenClaims["iss"] = "https://evil.example.com" },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "expired",
+			setup:   func(m *mockOIDC) { m.idTokenClaims["exp"] = time.Now().Add(-time.Hour).Unix() },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "foreign signature",
+			setup:   func(m *mockOIDC) { m.signingKey = otherKey },
+			wantErr: "invalid id_token",
+		},
+		{
+			name:    "userinfo for another subject",
+			setup:   func(m *mockOIDC) { m.userinfo["sub"] = "user-43" },
+			wantErr: "does not match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMockOIDC(t)
+			if tt.setup != nil {
+				tt.setup(m)
+			}
+
+			ctx := context.Background()
+			provider, err := NewOIDCProvider(ctx, "mock", m.server.URL, mockClientID, "secret", "http://localhost/oauth/callback/mock")
+			if err != nil {
+				t.Fatalf("NewOIDCProvider: %v", err)
+			}
+
+			profile, err := provider.Exchange(ctx, "good-code", "verifier", nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Exchange error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+
+			if profile.Provider != "mock" || profile.Subject != "user-42" {
+				t.Errorf("profile = %+v, want provider mock and subject user-42", profile)
+			}
+			if profile.Email != "alice@example.com" || !profile.EmailVerified {
+				t.Errorf("email = %q verified=%v, want verified alice@example.com", profile.Email, profile.EmailVerified)
+			}
+			if profile.Name != "Alice" {
+				t.Errorf("name = %q, want Alice", profile.Name)
+			}
+		})
+	}
+}
+
+func TestOIDCProviderRejectsForeignIssuer(t *testing.T) {
+	m := newMockOIDC(t)
+
+	_, err := NewOIDCProvider(context.Background(), "mock", m.server.URL+"/other", mockClientID, "secret", "http://localhost/cb")
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("NewOIDCProvider error = %v, want issuer mismatch", err)
+	}
+}
+
+func TestOIDCProviderAuthCodeURL(t *testing.T) {
+	m := newMockOIDC(t)
+
+	provider, err := NewOIDCProvider(context.Background(), "mock", m.server.URL, mockClientID, "secret", "http://localhost/cb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authURL := provider.AuthCodeURL("state-1", "challenge-1")
+	for _, part := range []string{"state=state-1", "code_challenge=challenge-1", "code_challenge_method=S256", "client_id=" + mockClientID} {
+		if !strings.Contains(authURL, part) {
+			t.Errorf("AuthCodeURL %q does not contain %q", authURL, part)
+		}
+	}
+}
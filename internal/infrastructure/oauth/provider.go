@@ -0,0 +1,142 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"auth-system/internal/application/interfaces"
+)
+
+// tokenResponse ответ токен-эндпоинта; поля сверх стандартных разбирают сами провайдеры
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+}
+
+// Provider OAuth2-провайдер с кодом авторизации и PKCE. Отличия конкретных
+// сервисов (дополнительные параметры, формат профиля) задаются функциями.
+type Provider struct {
+	name         string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	authURL      string
+	tokenURL     string
+	scopes       []string
+	httpClient   *http.Client
+
+	// extraTokenParams добавляет параметры из callback в запрос токена
+	extraTokenParams func(callback url.Values) url.Values
+	fetchProfile     func(ctx context.Context, p *Provider, token tokenResponse) (*interfaces.ExternalProfile, error)
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) AuthCodeURL(state, codeChallenge string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.clientID)
+	params.Set("redirect_uri", p.redirectURL)
+	params.Set("state", state)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+	if len(p.scopes) > 0 {
+		params.Set("scope", strings.Join(p.scopes, " "))
+	}
+
+	separator := "?"
+	if strings.Contains(p.authURL, "?") {
+		separator = "&"
+	}
+	return p.authURL + separator + params.Encode()
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string, callback url.Values) (*interfaces.ExternalProfile, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("client_id", p.clientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.clientSecret != "" {
+		form.Set("client_secret", p.clientSecret)
+	}
+	if p.extraTokenParams != nil {
+		for key, values := range p.extraTokenParams(callback) {
+			form[key] = values
+		}
+	}
+
+	var token tokenResponse
+	if err := p.postForm(ctx, p.tokenURL, form, &token); err != nil {
+		return nil, fmt.Errorf("%s token exchange: %w", p.name, err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%s token exchange: empty access token", p.name)
+	}
+
+	profile, err := p.fetchProfile(ctx, p, token)
+	if err != nil {
+		return nil, fmt.Errorf("%s profile: %w", p.name, err)
+	}
+	if profile.Subject == "" {
+		return nil, fmt.Errorf("%s profile: missing subject", p.name)
+	}
+	profile.Provider = p.name
+	return profile, nil
+}
+
+func (p *Provider) postForm(ctx context.Context, endpoint string, form url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	return p.do(req, out)
+}
+
+// getJSON запрашивает ресурс с токеном в заголовке Authorization
+func (p *Provider) getJSON(ctx context.Context, endpoint, authorization string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/json")
+	return p.do(req, out)
+}
+
+func (p *Provider) do(req *http.Request, out interface{}) error {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return errors.New("invalid JSON response")
+	}
+	return nil
+}
+
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
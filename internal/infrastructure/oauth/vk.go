@@ -0,0 +1,68 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"auth-system/internal/application/interfaces"
+)
+
+type vkUserInfo struct {
+	User struct {
+		UserID    flexibleID `json:"user_id"`
+		FirstName string     `json:"first_name"`
+		LastName  string     `json:"last_name"`
+		Avatar    string     `json:"avatar"`
+		Email     string     `json:"email"`
+	} `json:"user"`
+}
+
+// flexibleID VK ID отдает user_id то строкой, то числом
+type flexibleID string
+
+func (n *flexibleID) UnmarshalJSON(data []byte) error {
+	*n = flexibleID(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// NewVKProvider вход через VK ID. Токен-эндпоинт требует device_id из callback,
+// а профиль запрашивается POST-запросом с access_token в теле.
+func NewVKProvider(clientID, clientSecret, redirectURL string) *Provider {
+	return &Provider{
+		name:         "vk",
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		authURL:      "https://id.vk.com/authorize",
+		tokenURL:     "https://id.vk.com/oauth2/auth",
+		scopes:       []string{"email"},
+		httpClient:   defaultHTTPClient(),
+		extraTokenParams: func(callback url.Values) url.Values {
+			params := url.Values{}
+			params.Set("device_id", callback.Get("device_id"))
+			params.Set("state", callback.Get("state"))
+			return params
+		},
+		fetchProfile: func(ctx context.Context, p *Provider, token tokenResponse) (*interfaces.ExternalProfile, error) {
+			form := url.Values{}
+			form.Set("client_id", p.clientID)
+			form.Set("access_token", token.AccessToken)
+
+			var info vkUserInfo
+			if err := p.postForm(ctx, "https://id.vk.com/oauth2/user_info", form, &info); err != nil {
+				return nil, err
+			}
+
+			return &interfaces.ExternalProfile{
+				Subject: string(info.User.UserID),
+				Email:   info.User.Email,
+				// VK не сообщает, подтвержден ли адрес, поэтому по нему аккаунты не связываем
+				EmailVerified: false,
+				Name:          strings.TrimSpace(fmt.Sprintf("%s %s", info.User.FirstName, info.User.LastName)),
+				AvatarURL:     info.User.Avatar,
+			}, nil
+		},
+	}
+}
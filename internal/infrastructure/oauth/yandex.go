@@ -0,0 +1,54 @@
+package oauth
+
+import (
+	"context"
+
+	"auth-system/internal/application/interfaces"
+)
+
+type yandexProfile struct {
+	ID              string `json:"id"`
+	Login           string `json:"login"`
+	DefaultEmail    string `json:"default_email"`
+	RealName        string `json:"real_name"`
+	DefaultAvatarID string `json:"default_avatar_id"`
+	IsAvatarEmpty   bool   `json:"is_avatar_empty"`
+}
+
+// NewYandexProvider вход через Яндекс ID (OAuth2 + API профиля login.yandex.ru)
+func NewYandexProvider(clientID, clientSecret, redirectURL string) *Provider {
+	return &Provider{
+		name:         "yandex",
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		authURL:      "https://oauth.yandex.ru/authorize",
+		tokenURL:     "https://oauth.yandex.ru/token",
+		scopes:       []string{"login:email", "login:info", "login:avatar"},
+		httpClient:   defaultHTTPClient(),
+		fetchProfile: func(ctx context.Context, p *Provider, token tokenResponse) (*interfaces.ExternalProfile, error) {
+			var profile yandexProfile
+			if err := p.getJSON(ctx, "https://login.yandex.ru/info?format=json", "OAuth "+token.AccessToken, &profile); err != nil {
+				return nil, err
+			}
+
+			name := profile.RealName
+			if name == "" {
+				name = profile.Login
+			}
+			avatar := ""
+			if !profile.IsAvatarEmpty && profile.DefaultAvatarID != "" {
+				avatar = "https://avatars.yandex.net/get-yapic/" + profile.DefaultAvatarID + "/islands-200"
+			}
+
+			return &interfaces.ExternalProfile{
+				Subject: profile.ID,
+				Email:   profile.DefaultEmail,
+				// Основной адрес аккаунта Яндекса подтвержден самим Яндексом
+				EmailVerified: profile.DefaultEmail != "",
+				Name:          name,
+				AvatarURL:     avatar,
+			}, nil
+		},
+	}
+}
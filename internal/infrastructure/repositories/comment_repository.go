@@ -24,31 +24,99 @@ func (r *CommentRepository) Create(ctx context.Context, comment *entities.Commen
 
 func (r *CommentRepository) FindByID(ctx context.Context, id uint) (*entities.Comment, error) {
 	var comment entities.Comment
-	err := r.db.WithContext(ctx).
-		Preload("User").
-		Preload("Replies", "is_deleted = ?", false).
-		Preload("Replies.User").
-		First(&comment, id).Error
+	err := r.db.WithContext(ctx).First(&comment, id).Error
 	if err != nil {
 		return nil, err
 	}
-	return &comment, nil
+
+	comments := []entities.Comment{comment}
+	if err := r.attachUsers(ctx, comments); err != nil {
+		return nil, err
+	}
+	return &comments[0], nil
 }
 
-func (r *CommentRepository) FindByEventID(ctx context.Context, eventID uint) ([]entities.Comment, error) {
+// FindByEventID возвращает все комментарии мероприятия плоским списком,
+// начиная с корневых и не глубже maxDepth уровней вложенности
+func (r *CommentRepository) FindByEventID(ctx context.Context, eventID uint, maxDepth int) ([]entities.Comment, error) {
+	return r.findTree(ctx, "c.event_id = ? AND c.parent_id IS NULL", eventID, maxDepth)
+}
+
+// FindThread возвращает ветку, начинающуюся с комментария rootID
+func (r *CommentRepository) FindThread(ctx context.Context, rootID uint, maxDepth int) ([]entities.Comment, error) {
+	return r.findTree(ctx, "c.id = ?", rootID, maxDepth)
+}
+
+func (r *CommentRepository) findTree(ctx context.Context, rootCondition string, rootArg interface{}, maxDepth int) ([]entities.Comment, error) {
 	var comments []entities.Comment
-	err := r.db.WithContext(ctx).
-		Preload("User").
-		Preload("Replies", "is_deleted = ?", false).
-		Preload("Replies.User").
-		Where("event_id = ? AND parent_id IS NULL AND is_deleted = ?", eventID, false).
-		Order("created_at DESC").
-		Find(&comments).Error
-	return comments, err
+
+	// Рекурсивно обходим дерево по parent_id, reply_count нужен для отметки "продолжить ветку"
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT c.*, 0 AS depth
+			FROM comments c
+			WHERE `+rootCondition+`
+			UNION ALL
+			SELECT c.*, tree.depth + 1
+			FROM comments c
+			JOIN tree ON c.parent_id = tree.id
+			WHERE tree.depth < ?
+		)
+		SELECT tree.*,
+			`+replyCountSQL("tree")+` AS reply_count
+		FROM tree
+		ORDER BY tree.depth, tree.created_at DESC
+	`, rootArg, maxDepth).Scan(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.attachUsers(ctx, comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
 }
 
+// replyCountSQL считает ответы на комментарий alias без удаленных; удаленный ответ
+// учитывается, только если под ним есть свои ответы и он остается в дереве заглушкой
+func replyCountSQL(alias string) string {
+	return `(SELECT COUNT(*) FROM comments r WHERE r.parent_id = ` + alias + `.id
+		AND (r.is_deleted = false OR EXISTS (SELECT 1 FROM comments g WHERE g.parent_id = r.id)))`
+}
+
+// attachUsers загружает авторов комментариев одним запросом
+func (r *CommentRepository) attachUsers(ctx context.Context, comments []entities.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		userIDs = append(userIDs, comment.UserID)
+	}
+
+	var users []entities.User
+	if err := r.db.WithContext(ctx).Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return err
+	}
+
+	usersByID := make(map[uint]entities.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+	for i := range comments {
+		comments[i].User = usersByID[comments[i].UserID]
+	}
+	return nil
+}
+
+// Update сохраняет текст и пометку об удалении; счетчики голосов меняет только Vote,
+// поэтому полный Save затер бы голоса, отданные после чтения комментария
 func (r *CommentRepository) Update(ctx context.Context, comment *entities.Comment) error {
-	return r.db.WithContext(ctx).Save(comment).Error
+	return r.db.WithContext(ctx).
+		Model(comment).
+		Select("content", "is_deleted", "updated_at").
+		Updates(comment).Error
 }
 
 func (r *CommentRepository) SoftDelete(ctx context.Context, id uint) error {
@@ -108,6 +176,32 @@ func (r *CommentRepository) GetScore(ctx context.Context, commentID uint) (int,
 	return int(upvotes - downvotes), nil
 }
 
+// RecountVotes пересчитывает счетчики голосов комментария по comment_votes
+func (r *CommentRepository) RecountVotes(ctx context.Context, commentID uint) (int, int, error) {
+	var counts struct {
+		Upvotes   int
+		Downvotes int
+	}
+
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE comments SET
+			upvotes = v.upvotes,
+			downvotes = v.downvotes,
+			score = v.upvotes - v.downvotes
+		FROM (
+			SELECT
+				COUNT(*) FILTER (WHERE vote_type = 'upvote') AS upvotes,
+				COUNT(*) FILTER (WHERE vote_type = 'downvote') AS downvotes
+			FROM comment_votes
+			WHERE comment_id = ?
+		) v
+		WHERE comments.id = ?
+		RETURNING comments.upvotes, comments.downvotes
+	`, commentID, commentID).Scan(&counts).Error
+
+	return counts.Upvotes, counts.Downvotes, err
+}
+
 // Новые методы, которые нужно добавить:
 
 func (r *CommentRepository) DeleteVote(ctx context.Context, commentID, userID uint) error {
@@ -123,3 +217,57 @@ func (r *CommentRepository) UpdateVote(ctx context.Context, vote *entities.Comme
 func (r *CommentRepository) CreateVote(ctx context.Context, vote *entities.CommentVote) error {
 	return r.db.WithContext(ctx).Create(vote).Error
 }
+
+func (r *CommentRepository) FindByUser(ctx context.Context, userID uint, filter map[string]interface{}, limit, offset int) ([]entities.Comment, int64, error) {
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Table("comments c").
+			Joins("JOIN events e ON e.id = c.event_id").
+			Where("c.is_deleted = ?", false)
+
+		if includeReplies, ok := filter["include_replies"].(bool); ok && includeReplies {
+			db = db.Where("(c.user_id = ? OR (c.user_id <> ? AND c.parent_id IN (SELECT id FROM comments WHERE user_id = ?)))", userID, userID, userID)
+		} else {
+			db = db.Where("c.user_id = ?", userID)
+		}
+
+		if eventID, ok := filter["event_id"]; ok {
+			db = db.Where("c.event_id = ?", eventID)
+		}
+		return db
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Scopes(scope).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []entities.Comment
+	err := r.db.WithContext(ctx).Scopes(scope).
+		Select(`c.*, e.title AS event_title,
+			` + replyCountSQL("c") + ` AS reply_count`).
+		Order("c.created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.attachUsers(ctx, comments); err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+func (r *CommentRepository) GetUserStats(ctx context.Context, userID uint) (int64, int64, error) {
+	var stats struct {
+		Count int64
+		Karma int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&entities.Comment{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(upvotes - downvotes), 0) AS karma").
+		Where("user_id = ? AND is_deleted = ?", userID, false).
+		Scan(&stats).Error
+	return stats.Count, stats.Karma, err
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"auth-system/internal/application/interfaces"
@@ -38,12 +39,12 @@ func (r *EventRepository) FindByID(ctx context.Context, id uint) (*entities.Even
 		return nil, err
 	}
 
-	// Ручной расчет количества участников
-	var participantsCount int64
-	r.db.WithContext(ctx).Table("event_participants").
-		Where("event_id = ? AND status = 'going'", id).
-		Count(&participantsCount)
-	event.ParticipantsCount = int(participantsCount)
+	// Счетчики участников по статусам
+	counts, err := r.participantCounts(ctx, []uint{id})
+	if err != nil {
+		return nil, err
+	}
+	event.SetRSVPCounts(counts[id])
 
 	// Преобразуем координаты из PostGIS
 	var coords struct {
@@ -61,29 +62,47 @@ func (r *EventRepository) FindByID(ctx context.Context, id uint) (*entities.Even
 	event.Latitude = coords.Latitude
 	event.Longitude = coords.Longitude
 
+	// Медиафайлы мероприятия
+	media, err := r.GetMedia(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	event.Media = media
+
 	return &event, nil
 }
 
 func (r *EventRepository) FindAll(ctx context.Context, filter map[string]interface{}) ([]entities.Event, error) {
 	var events []entities.Event
 
+	selectSQL := "events.*, users.username, users.email, users.role, users.avatar_url"
+	var selectArgs []interface{}
+
+	// Расстояние до точки поиска в километрах
+	center, hasCenter := filter["center"].(entities.GeoPoint)
+	if hasCenter {
+		selectSQL += ", ST_Distance(events.location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography) / 1000 AS distance_km"
+		selectArgs = append(selectArgs, center.Longitude, center.Latitude)
+	}
+
 	// Базовый запрос
 	query := r.db.WithContext(ctx).
 		Table("events").
-		Select("events.*, users.username, users.email, users.role, users.avatar_url").
+		Select(selectSQL, selectArgs...).
 		Joins("LEFT JOIN users ON events.creator_id = users.id").
 		Where("events.is_active = ?", true)
 
 	// Фильтры
-	if types, ok := filter["type"]; ok {
-		query = query.Where("events.type IN ?", types)
-	}
-	if date, ok := filter["date"]; ok {
-		if !date.(time.Time).IsZero() {
-			startOfDay := date.(time.Time).Truncate(24 * time.Hour)
-			endOfDay := startOfDay.Add(24 * time.Hour)
-			query = query.Where("events.event_date BETWEEN ? AND ?", startOfDay, endOfDay)
+	query = applyEventFilters(query, filter)
+
+	// Сортировка
+	switch filter["sort"] {
+	case "distance":
+		if hasCenter {
+			query = query.Order("distance_km ASC")
 		}
+	case "date":
+		query = query.Order("events.event_date ASC")
 	}
 
 	// Получаем события
@@ -92,15 +111,12 @@ func (r *EventRepository) FindAll(ctx context.Context, filter map[string]interfa
 		return nil, err
 	}
 
+	if err := r.attachParticipantCounts(ctx, events); err != nil {
+		return nil, err
+	}
+
 	// Для каждого события получаем дополнительные данные
 	for i := range events {
-		// Количество участников
-		var count int64
-		r.db.WithContext(ctx).Table("event_participants").
-			Where("event_id = ? AND status = 'going'", events[i].ID).
-			Count(&count)
-		events[i].ParticipantsCount = int(count)
-
 		// Координаты
 		var coords struct {
 			Latitude  float64
@@ -117,9 +133,190 @@ func (r *EventRepository) FindAll(ctx context.Context, filter map[string]interfa
 		events[i].Longitude = coords.Longitude
 	}
 
+	// Медиафайлы загружаем одним запросом для всех мероприятий
+	if err := r.attachMedia(ctx, events); err != nil {
+		return nil, err
+	}
+
 	return events, nil
 }
 
+// participantCounts считает участников по статусам для нескольких мероприятий одним запросом
+func (r *EventRepository) participantCounts(ctx context.Context, eventIDs []uint) (map[uint]map[string]int, error) {
+	var rows []struct {
+		EventID uint
+		Status  string
+		Count   int
+	}
+	err := r.db.WithContext(ctx).
+		Table("event_participants").
+		Select("event_id, status, COUNT(*) AS count").
+		Where("event_id IN ?", eventIDs).
+		Group("event_id, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]map[string]int)
+	for _, row := range rows {
+		if counts[row.EventID] == nil {
+			counts[row.EventID] = make(map[string]int)
+		}
+		counts[row.EventID][row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *EventRepository) attachParticipantCounts(ctx context.Context, events []entities.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	eventIDs := make([]uint, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID
+	}
+
+	counts, err := r.participantCounts(ctx, eventIDs)
+	if err != nil {
+		return err
+	}
+	for i := range events {
+		events[i].SetRSVPCounts(counts[events[i].ID])
+	}
+	return nil
+}
+
+// attachTags загружает теги нескольких мероприятий одним запросом
+func (r *EventRepository) attachTags(ctx context.Context, events []entities.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	eventIDs := make([]uint, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID
+	}
+
+	var rows []struct {
+		EventID uint
+		entities.Tag
+	}
+	err := r.db.WithContext(ctx).
+		Table("tags").
+		Select("event_tags.event_id, tags.*").
+		Joins("JOIN event_tags ON tags.id = event_tags.tag_id").
+		Where("event_tags.event_id IN ?", eventIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	tagsByEvent := make(map[uint][]entities.Tag)
+	for _, row := range rows {
+		tagsByEvent[row.EventID] = append(tagsByEvent[row.EventID], row.Tag)
+	}
+	for i := range events {
+		events[i].Tags = tagsByEvent[events[i].ID]
+	}
+	return nil
+}
+
+// attachListData дополняет списки мероприятий тегами и счетчиками участников
+func (r *EventRepository) attachListData(ctx context.Context, events []entities.Event) error {
+	if err := r.attachTags(ctx, events); err != nil {
+		return err
+	}
+	return r.attachParticipantCounts(ctx, events)
+}
+
+func (r *EventRepository) attachMedia(ctx context.Context, events []entities.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	eventIDs := make([]uint, len(events))
+	for i, event := range events {
+		eventIDs[i] = event.ID
+	}
+
+	var media []entities.EventMedia
+	err := r.db.WithContext(ctx).
+		Where("event_id IN ?", eventIDs).
+		Order("order_index ASC, id ASC").
+		Find(&media).Error
+	if err != nil {
+		return err
+	}
+
+	mediaByEvent := make(map[uint][]entities.EventMedia)
+	for _, m := range media {
+		mediaByEvent[m.EventID] = append(mediaByEvent[m.EventID], m)
+	}
+	for i := range events {
+		events[i].Media = mediaByEvent[events[i].ID]
+	}
+	return nil
+}
+
+// GetClusters группирует активные мероприятия по ячейкам сетки размером gridSize градусов
+func (r *EventRepository) GetClusters(ctx context.Context, filter map[string]interface{}, gridSize float64) ([]entities.EventCluster, error) {
+	var clusters []entities.EventCluster
+
+	query := r.db.WithContext(ctx).
+		Table("events").
+		Select(`
+			ST_SnapToGrid(events.location::geometry, ?) AS cell,
+			COUNT(*) AS count,
+			AVG(ST_Y(events.location::geometry)) AS latitude,
+			AVG(ST_X(events.location::geometry)) AS longitude,
+			MIN(ST_Y(events.location::geometry)) AS min_lat,
+			MIN(ST_X(events.location::geometry)) AS min_lon,
+			MAX(ST_Y(events.location::geometry)) AS max_lat,
+			MAX(ST_X(events.location::geometry)) AS max_lon,
+			MIN(events.id) AS event_id
+		`, gridSize).
+		Where("events.is_active = ? AND events.location IS NOT NULL", true)
+
+	query = applyEventFilters(query, filter)
+
+	err := query.Group("cell").Scan(&clusters).Error
+	return clusters, err
+}
+
+// applyEventFilters добавляет к запросу по таблице events условия из фильтра
+func applyEventFilters(query *gorm.DB, filter map[string]interface{}) *gorm.DB {
+	if types, ok := filter["type"]; ok {
+		query = query.Where("events.type IN ?", types)
+	}
+	if date, ok := filter["date"]; ok {
+		if !date.(time.Time).IsZero() {
+			startOfDay := date.(time.Time).Truncate(24 * time.Hour)
+			endOfDay := startOfDay.Add(24 * time.Hour)
+			query = query.Where("events.event_date BETWEEN ? AND ?", startOfDay, endOfDay)
+		}
+	}
+	if ids, ok := filter["ids"]; ok {
+		query = query.Where("events.id IN ?", ids)
+	}
+	if bbox, ok := filter["bbox"].(entities.BoundingBox); ok {
+		query = query.Where(
+			"events.location::geometry && ST_MakeEnvelope(?, ?, ?, ?, 4326)",
+			bbox.MinLon, bbox.MinLat, bbox.MaxLon, bbox.MaxLat,
+		)
+	}
+	if radius, ok := filter["radius_m"].(float64); ok {
+		if center, ok := filter["center"].(entities.GeoPoint); ok {
+			query = query.Where(
+				"ST_DWithin(events.location, ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography, ?)",
+				center.Longitude, center.Latitude, radius,
+			)
+		}
+	}
+	return query
+}
+
 func (r *EventRepository) GetEventTags(ctx context.Context, eventID uint) ([]entities.Tag, error) {
 	var tags []entities.Tag
 
@@ -133,21 +330,74 @@ func (r *EventRepository) GetEventTags(ctx context.Context, eventID uint) ([]ent
 	return tags, err
 }
 
-func (r *EventRepository) GetEventParticipants(ctx context.Context, eventID uint) ([]entities.EventParticipant, error) {
-	var participants []entities.EventParticipant
+// GetEventParticipants участники мероприятия в порядке записи; limit 0 - без ограничения
+func (r *EventRepository) GetEventParticipants(ctx context.Context, eventID uint, status string, limit, offset int) ([]entities.EventParticipant, int64, error) {
+	scope := func(db *gorm.DB) *gorm.DB {
+		db = db.Model(&entities.EventParticipant{}).Where("event_id = ?", eventID)
+		if status != "" {
+			db = db.Where("status = ?", status)
+		}
+		return db
+	}
 
-	err := r.db.WithContext(ctx).
-		Table("event_participants").
-		Select("event_participants.*, users.username, users.email, users.role, users.avatar_url").
-		Joins("LEFT JOIN users ON event_participants.user_id = users.id").
-		Where("event_participants.event_id = ?", eventID).
-		Find(&participants).Error
+	var total int64
+	if err := r.db.WithContext(ctx).Scopes(scope).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := r.db.WithContext(ctx).Scopes(scope).Preload("User").Order("joined_at ASC, user_id ASC")
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
 
-	return participants, err
+	var participants []entities.EventParticipant
+	if err := query.Find(&participants).Error; err != nil {
+		return nil, 0, err
+	}
+	return participants, total, nil
 }
 
+// Update сохраняет редактируемые поля мероприятия, пока оно не отменено. Колонки
+// перечислены явно: копия прочитана заранее, и полный Save затер бы отмену или проверку.
+// При переносе даты журнал отправленных напоминаний очищается, чтобы они пришли к новой дате
 func (r *EventRepository) Update(ctx context.Context, event *entities.Event) error {
-	return r.db.WithContext(ctx).Save(event).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current struct {
+			EventDate time.Time
+		}
+		result := tx.Raw("SELECT event_date FROM events WHERE id = ? AND cancelled_at IS NULL FOR UPDATE", event.ID).
+			Scan(&current)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrEventCancelled
+		}
+
+		err := tx.Model(&entities.Event{}).
+			Where("id = ?", event.ID).
+			Select("title", "description", "event_date", "type", "max_participants", "price", "updated_at").
+			Updates(event).Error
+		if err != nil {
+			return err
+		}
+
+		if current.EventDate.Equal(event.EventDate) {
+			return nil
+		}
+		return tx.Exec("DELETE FROM event_reminders WHERE event_id = ?", event.ID).Error
+	})
+}
+
+// Deactivate скрывает мероприятие, не удаляя его
+func (r *EventRepository) Deactivate(ctx context.Context, eventID uint) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.Event{}).
+		Where("id = ?", eventID).
+		Updates(map[string]interface{}{
+			"is_active":  false,
+			"updated_at": time.Now(),
+		}).Error
 }
 
 func (r *EventRepository) Delete(ctx context.Context, id uint) error {
@@ -158,26 +408,29 @@ func (r *EventRepository) GetByCreator(ctx context.Context, creatorID uint) ([]e
 	var events []entities.Event
 	err := r.db.WithContext(ctx).
 		Preload("Creator").
-		Preload("Tags").
 		Where("creator_id = ? AND is_active = ?", creatorID, true).
 		Order("created_at DESC").
 		Find(&events).Error
-	return events, err
+	if err != nil {
+		return nil, err
+	}
+	return events, r.attachListData(ctx, events)
 }
 
-func (r *EventRepository) GetParticipatedEvents(ctx context.Context, userID uint) ([]entities.Event, error) {
+func (r *EventRepository) GetParticipatedEvents(ctx context.Context, userID uint, status string) ([]entities.Event, error) {
 	var events []entities.Event
 
-	// Получаем мероприятия, в которых участвует пользователь
+	// Получаем мероприятия, отмеченные пользователем с заданным статусом
 	err := r.db.WithContext(ctx).
 		Joins("JOIN event_participants ep ON events.id = ep.event_id").
 		Preload("Creator").
-		Preload("Tags").
-		Where("ep.user_id = ? AND ep.status = 'going' AND events.is_active = ?", userID, true).
+		Where("ep.user_id = ? AND ep.status = ? AND events.is_active = ?", userID, status, true).
 		Order("ep.joined_at DESC").
 		Find(&events).Error
-
-	return events, err
+	if err != nil {
+		return nil, err
+	}
+	return events, r.attachListData(ctx, events)
 }
 
 func (r *EventRepository) VerifyEvent(ctx context.Context, eventID uint) error {
@@ -201,11 +454,13 @@ func (r *EventRepository) GetPendingEvents(ctx context.Context) ([]entities.Even
 	var events []entities.Event
 	err := r.db.WithContext(ctx).
 		Preload("Creator").
-		Preload("Tags").
 		Where("is_verified = ? AND is_active = ?", false, true).
 		Order("created_at DESC").
 		Find(&events).Error
-	return events, err
+	if err != nil {
+		return nil, err
+	}
+	return events, r.attachListData(ctx, events)
 }
 
 func (r *EventRepository) GetStatistics(ctx context.Context) (map[string]interface{}, error) {
@@ -214,20 +469,216 @@ func (r *EventRepository) GetStatistics(ctx context.Context) (map[string]interfa
 	return make(map[string]interface{}), nil
 }
 
-func (r *EventRepository) AddParticipant(ctx context.Context, eventID, userID uint) error {
-	participant := &entities.EventParticipant{
-		EventID:  eventID,
-		UserID:   userID,
-		Status:   "going",
-		JoinedAt: time.Now(),
+// JoinEvent атомарно устанавливает статус участия. Для going строка мероприятия
+// блокируется на время проверки мест, поэтому параллельные записи не превышают
+// MaxParticipants. Возвращает итоговый статус и переведенных из очереди, если
+// пользователь освободил место.
+func (r *EventRepository) JoinEvent(ctx context.Context, eventID, userID uint, status string, allowWaitlist bool) (string, []uint, error) {
+	var promoted []uint
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		event, err := lockEvent(tx, eventID)
+		if err != nil {
+			return err
+		}
+		if event.CancelledAt != nil {
+			return entities.ErrEventCancelled
+		}
+		maxParticipants := event.MaxParticipants
+
+		var existing *entities.EventParticipant
+		var found entities.EventParticipant
+		err = tx.Where("event_id = ? AND user_id = ?", eventID, userID).First(&found).Error
+		switch {
+		case err == nil:
+			existing = &found
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		if existing != nil && status == entities.ParticipantStatusGoing {
+			if existing.IsWaitlisted() {
+				return entities.ErrAlreadyWaitlisted
+			}
+			if existing.Status == entities.ParticipantStatusGoing {
+				return entities.ErrAlreadyParticipating
+			}
+		}
+		if existing != nil && existing.Status == status {
+			return nil
+		}
+
+		if status == entities.ParticipantStatusGoing && maxParticipants != nil {
+			var going, waiting int64
+			if err := tx.Raw(`
+				SELECT COUNT(*) FILTER (WHERE status = 'going'), COUNT(*) FILTER (WHERE status = 'waitlisted')
+				FROM event_participants WHERE event_id = ?
+			`, eventID).Row().Scan(&going, &waiting); err != nil {
+				return err
+			}
+
+			// Свободное место, за которым уже стоит очередь, достается очереди
+			if going >= int64(*maxParticipants) || waiting > 0 {
+				if !allowWaitlist {
+					return entities.ErrEventFull
+				}
+				status = entities.ParticipantStatusWaitlisted
+			}
+		}
+
+		if existing == nil {
+			return tx.Create(&entities.EventParticipant{
+				EventID:  eventID,
+				UserID:   userID,
+				Status:   status,
+				JoinedAt: time.Now(),
+			}).Error
+		}
+
+		if err := tx.Model(&entities.EventParticipant{}).
+			Where("event_id = ? AND user_id = ?", eventID, userID).
+			Updates(map[string]interface{}{"status": status, "joined_at": time.Now()}).Error; err != nil {
+			return err
+		}
+
+		// Пользователь передумал идти - его место достается очереди
+		if existing.Status == entities.ParticipantStatusGoing {
+			promoted, err = promoteLocked(tx, eventID, maxParticipants)
+		}
+		return err
+	})
+
+	return status, promoted, err
+}
+
+// CancelEvent отмечает мероприятие отмененным; повторная отмена возвращает entities.ErrEventCancelled
+func (r *EventRepository) CancelEvent(ctx context.Context, eventID uint, reason string) error {
+	result := r.db.WithContext(ctx).
+		Model(&entities.Event{}).
+		Where("id = ? AND cancelled_at IS NULL", eventID).
+		Updates(map[string]interface{}{
+			"cancelled_at":  time.Now(),
+			"cancel_reason": reason,
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
 	}
-	return r.db.WithContext(ctx).Create(participant).Error
+	if result.RowsAffected == 0 {
+		return entities.ErrEventCancelled
+	}
+	return nil
 }
 
-func (r *EventRepository) RemoveParticipant(ctx context.Context, eventID, userID uint) error {
-	return r.db.WithContext(ctx).
-		Where("event_id = ? AND user_id = ?", eventID, userID).
-		Delete(&entities.EventParticipant{}).Error
+func (r *EventRepository) GetParticipantIDs(ctx context.Context, eventID uint, statuses []string) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.WithContext(ctx).
+		Model(&entities.EventParticipant{}).
+		Where("event_id = ? AND status IN ?", eventID, statuses).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// LeaveEvent удаляет участника и в той же транзакции отдает освободившееся место очереди.
+// Возвращает идентификаторы переведенных из очереди.
+func (r *EventRepository) LeaveEvent(ctx context.Context, eventID, userID uint) ([]uint, error) {
+	var promoted []uint
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		event, err := lockEvent(tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("event_id = ? AND user_id = ?", eventID, userID).
+			Delete(&entities.EventParticipant{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrNotParticipating
+		}
+
+		// У отмененного мероприятия очередь больше не движется
+		if event.CancelledAt != nil {
+			return nil
+		}
+		promoted, err = promoteLocked(tx, eventID, event.MaxParticipants)
+		return err
+	})
+
+	return promoted, err
+}
+
+func (r *EventRepository) PromoteWaitlisted(ctx context.Context, eventID uint) ([]uint, error) {
+	var promoted []uint
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		event, err := lockEvent(tx, eventID)
+		if err != nil {
+			return err
+		}
+		if event.CancelledAt != nil {
+			return nil
+		}
+
+		promoted, err = promoteLocked(tx, eventID, event.MaxParticipants)
+		return err
+	})
+
+	return promoted, err
+}
+
+// lockedEvent данные мероприятия, прочитанные под блокировкой строки
+type lockedEvent struct {
+	ID              uint
+	MaxParticipants *int
+	CancelledAt     *time.Time
+}
+
+// lockEvent блокирует строку мероприятия до конца транзакции
+func lockEvent(tx *gorm.DB, eventID uint) (*lockedEvent, error) {
+	var event lockedEvent
+	if err := tx.Raw("SELECT id, max_participants, cancelled_at FROM events WHERE id = ? FOR UPDATE", eventID).
+		Scan(&event).Error; err != nil {
+		return nil, err
+	}
+	if event.ID == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &event, nil
+}
+
+// promoteLocked переводит очередь на свободные места; вызывается под lockEvent
+func promoteLocked(tx *gorm.DB, eventID uint, maxParticipants *int) ([]uint, error) {
+	var going int64
+	if err := tx.Table("event_participants").
+		Where("event_id = ? AND status = 'going'", eventID).
+		Count(&going).Error; err != nil {
+		return nil, err
+	}
+
+	// Без ограничения мест в очередь переводятся все
+	free := -1
+	if maxParticipants != nil {
+		free = *maxParticipants - int(going)
+		if free <= 0 {
+			return nil, nil
+		}
+	}
+
+	var promoted []uint
+	err := tx.Raw(`
+		UPDATE event_participants SET status = 'going', joined_at = now()
+		WHERE event_id = ? AND user_id IN (
+			SELECT user_id FROM event_participants
+			WHERE event_id = ? AND status = 'waitlisted'
+			ORDER BY joined_at, user_id
+			LIMIT NULLIF(?, -1)
+		)
+		RETURNING user_id
+	`, eventID, eventID, free).Scan(&promoted).Error
+	return promoted, err
 }
 
 func (r *EventRepository) GetParticipantCount(ctx context.Context, eventID uint) (int64, error) {
@@ -239,13 +690,40 @@ func (r *EventRepository) GetParticipantCount(ctx context.Context, eventID uint)
 	return count, err
 }
 
-func (r *EventRepository) IsParticipant(ctx context.Context, eventID, userID uint) (bool, error) {
-	var count int64
+func (r *EventRepository) FindParticipant(ctx context.Context, eventID, userID uint) (*entities.EventParticipant, error) {
+	var participant entities.EventParticipant
 	err := r.db.WithContext(ctx).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		First(&participant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+func (r *EventRepository) SetRemindersMuted(ctx context.Context, eventID, userID uint, muted bool) error {
+	result := r.db.WithContext(ctx).
 		Model(&entities.EventParticipant{}).
-		Where("event_id = ? AND user_id = ? AND status = 'going'", eventID, userID).
-		Count(&count).Error
-	return count > 0, err
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Update("reminders_muted", muted)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrNotParticipating
+	}
+	return nil
+}
+
+func (r *EventRepository) GetWaitlistPosition(ctx context.Context, eventID, userID uint) (int64, error) {
+	var position int64
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*) FROM event_participants w
+		JOIN event_participants me ON me.event_id = w.event_id AND me.user_id = ?
+		WHERE w.event_id = ? AND w.status = 'waitlisted'
+			AND (w.joined_at, w.user_id) <= (me.joined_at, me.user_id)
+	`, userID, eventID).Scan(&position).Error
+	return position, err
 }
 
 func (r *EventRepository) AddTags(ctx context.Context, eventID uint, tags []string) error {
@@ -282,6 +760,66 @@ func (r *EventRepository) AddTags(ctx context.Context, eventID uint, tags []stri
 	return tx.Commit().Error
 }
 
+func (r *EventRepository) AddMedia(ctx context.Context, media *entities.EventMedia) error {
+	return r.db.WithContext(ctx).Create(media).Error
+}
+
+func (r *EventRepository) GetMedia(ctx context.Context, eventID uint) ([]entities.EventMedia, error) {
+	var media []entities.EventMedia
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("order_index ASC, id ASC").
+		Find(&media).Error
+	return media, err
+}
+
+func (r *EventRepository) FindMediaByID(ctx context.Context, mediaID uint) (*entities.EventMedia, error) {
+	var media entities.EventMedia
+	if err := r.db.WithContext(ctx).First(&media, mediaID).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// FinishMediaProcessing записывает результат обработки, только пока файл ждет ее.
+// false означает, что файл успели удалить: Save в этом случае вставил бы строку заново
+func (r *EventRepository) FinishMediaProcessing(ctx context.Context, media *entities.EventMedia) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.EventMedia{}).
+		Where("id = ? AND status = ?", media.ID, entities.MediaStatusProcessing).
+		Select("file_url", "card_url", "thumbnail_url", "file_type", "status").
+		Updates(media)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *EventRepository) GetMediaByStatus(ctx context.Context, status string) ([]entities.EventMedia, error) {
+	var media []entities.EventMedia
+	err := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("id ASC").
+		Find(&media).Error
+	return media, err
+}
+
+func (r *EventRepository) DeleteMedia(ctx context.Context, mediaID uint) error {
+	return r.db.WithContext(ctx).Delete(&entities.EventMedia{}, mediaID).Error
+}
+
+// ReorderMedia выставляет order_index по порядку идентификаторов в mediaIDs
+func (r *EventRepository) ReorderMedia(ctx context.Context, eventID uint, mediaIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, mediaID := range mediaIDs {
+			err := tx.Model(&entities.EventMedia{}).
+				Where("id = ? AND event_id = ?", mediaID, eventID).
+				Update("order_index", i).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *EventRepository) GetTopEvents(ctx context.Context, limit int) ([]map[string]interface{}, error) {
 	// Этот метод теперь находится в AdminRepository
 	// Оставляем пустую реализацию для совместимости
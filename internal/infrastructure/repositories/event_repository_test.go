@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-system/internal/domain/entities"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB подключается к TEST_DATABASE_URL и создает отдельную схему с таблицами,
+// которые нужны записи на мероприятия; схема удаляется после теста
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	config := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	schema := fmt.Sprintf("join_test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	// search_path задается в DSN, чтобы его получило каждое соединение пула
+	if strings.Contains(dsn, "://") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatalf("connect to schema: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(20)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	for _, statement := range []string{
+		`CREATE TABLE events (
+			id bigserial PRIMARY KEY,
+			max_participants integer,
+			cancelled_at timestamp with time zone
+		)`,
+		`CREATE TABLE event_participants (
+			event_id bigint NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			user_id bigint NOT NULL,
+			status text NOT NULL DEFAULT 'going',
+			joined_at timestamp with time zone,
+			reminders_muted boolean NOT NULL DEFAULT false,
+			PRIMARY KEY (event_id, user_id)
+		)`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("create tables: %v", err)
+		}
+	}
+
+	return db
+}
+
+func createTestEvent(t *testing.T, db *gorm.DB, maxParticipants int) uint {
+	t.Helper()
+
+	var id uint
+	if err := db.Raw("INSERT INTO events (max_participants) VALUES (?) RETURNING id", maxParticipants).
+		Scan(&id).Error; err != nil {
+		t.Fatalf("create event: %v", err)
+	}
+	return id
+}
+
+func countByStatus(t *testing.T, db *gorm.DB, eventID uint, status string) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Table("event_participants").
+		Where("event_id = ? AND status = ?", eventID, status).
+		Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+// joinConcurrently записывает users пользователей одновременно и возвращает ошибки по порядку
+func joinConcurrently(repo *EventRepository, eventID uint, users int, allowWaitlist bool) []error {
+	errs := make([]error, users)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, _, errs[i] = repo.JoinEvent(context.Background(), eventID, uint(i+1), entities.ParticipantStatusGoing, allowWaitlist)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+func TestJoinEventConcurrentCapacity(t *testing.T) {
+	const (
+		maxParticipants = 5
+		users           = 40
+	)
+
+	tests := []struct {
+		name          string
+		allowWaitlist bool
+	}{
+		{name: "extra users are waitlisted", allowWaitlist: true},
+		{name: "extra users get ErrEventFull", allowWaitlist: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t)
+			repo := &EventRepository{db: db}
+			eventID := createTestEvent(t, db, maxParticipants)
+
+			errs := joinConcurrently(repo, eventID, users, tt.allowWaitlist)
+
+			var full int
+			for _, err := range errs {
+				switch {
+				case err == nil:
+				case errors.Is(err, entities.ErrEventFull) && !tt.allowWaitlist:
+					full++
+				default:
+					t.Fatalf("JoinEvent: unexpected error %v", err)
+				}
+			}
+
+			going := countByStatus(t, db, eventID, entities.ParticipantStatusGoing)
+			waitlisted := countByStatus(t, db, eventID, entities.ParticipantStatusWaitlisted)
+
+			if going != maxParticipants {
+				t.Errorf("going = %d, want %d", going, maxParticipants)
+			}
+			if tt.allowWaitlist {
+				if waitlisted != users-maxParticipants {
+					t.Errorf("waitlisted = %d, want %d", waitlisted, users-maxParticipants)
+				}
+			} else {
+				if waitlisted != 0 || full != users-maxParticipants {
+					t.Errorf("waitlisted = %d, full = %d, want 0 and %d", waitlisted, full, users-maxParticipants)
+				}
+			}
+		})
+	}
+}
+
+func TestJoinEventConcurrentSameUser(t *testing.T) {
+	db := openTestDB(t)
+	repo := &EventRepository{db: db}
+	eventID := createTestEvent(t, db, 10)
+
+	const attempts = 10
+	errs := make([]error, attempts)
+	start := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, _, errs[i] = repo.JoinEvent(context.Background(), eventID, 1, entities.ParticipantStatusGoing, true)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	var joined int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			joined++
+		case errors.Is(err, entities.ErrAlreadyParticipating):
+		default:
+			t.Fatalf("JoinEvent: unexpected error %v", err)
+		}
+	}
+
+	if joined != 1 {
+		t.Errorf("successful joins = %d, want 1", joined)
+	}
+	if going := countByStatus(t, db, eventID, entities.ParticipantStatusGoing); going != 1 {
+		t.Errorf("going = %d, want 1", going)
+	}
+}
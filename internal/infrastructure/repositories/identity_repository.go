@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) interfaces.IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, identity *entities.UserIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+func (r *IdentityRepository) FindByProviderSubject(ctx context.Context, provider, subject string) (*entities.UserIdentity, error) {
+	var identity entities.UserIdentity
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *IdentityRepository) FindByUser(ctx context.Context, userID uint) ([]entities.UserIdentity, error) {
+	var identities []entities.UserIdentity
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&identities).Error
+	return identities, err
+}
+
+func (r *IdentityRepository) TouchLogin(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.UserIdentity{}).
+		Where("id = ?", id).
+		Update("last_login_at", time.Now()).Error
+}
+
+func (r *IdentityRepository) Delete(ctx context.Context, userID uint, provider string) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND provider = ?", userID, provider).
+		Delete(&entities.UserIdentity{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *IdentityRepository) CreateState(ctx context.Context, state *entities.OAuthState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+// ConsumeState удаляет state и возвращает его; повторный callback с тем же state не пройдет
+func (r *IdentityRepository) ConsumeState(ctx context.Context, stateHash string) (*entities.OAuthState, error) {
+	var states []entities.OAuthState
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
+
+// DeleteExpiredStates чистит брошенные попытки входа
+func (r *IdentityRepository) DeleteExpiredStates(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&entities.OAuthState{}).Error
+}
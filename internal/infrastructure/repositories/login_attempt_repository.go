@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) interfaces.LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (r *LoginAttemptRepository) Create(ctx context.Context, attempt *entities.LoginAttempt) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+// Begin записывает начатую попытку и возвращает неудачи, накопленные до нее.
+// Попытки с одной почты и с одного IP выстраиваются в очередь на advisory-блокировках:
+// иначе параллельные запросы прочитают счетчики раньше, чем увидят записи друг друга
+func (r *LoginAttemptRepository) Begin(ctx context.Context, attempt *entities.LoginAttempt, since time.Time) (*entities.LoginFailures, error) {
+	failures := &entities.LoginFailures{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Порядок блокировок всегда одинаковый: сначала почта, потом IP
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(1, hashtext(?))", attempt.Email).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(2, hashtext(?))", attempt.IP).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if failures.Account, failures.AccountLast, err = accountFailures(tx, attempt.Email, since); err != nil {
+			return err
+		}
+		if failures.IP, failures.IPLast, err = countFailures(tx, "ip = ?", attempt.IP, since); err != nil {
+			return err
+		}
+
+		attempt.Success = false
+		attempt.Reason = entities.LoginReasonPending
+		return tx.Create(attempt).Error
+	})
+	return failures, err
+}
+
+// Finish записывает итог начатой попытки
+func (r *LoginAttemptRepository) Finish(ctx context.Context, id uint, success bool, reason string) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.LoginAttempt{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"success": success, "reason": reason}).Error
+}
+
+// AccountFailures считает неудачные входы в аккаунт подряд, начиная с since или последнего успешного входа
+func (r *LoginAttemptRepository) AccountFailures(ctx context.Context, email string, since time.Time) (int64, time.Time, error) {
+	return accountFailures(r.db.WithContext(ctx), email, since)
+}
+
+func accountFailures(db *gorm.DB, email string, since time.Time) (int64, time.Time, error) {
+	var lastSuccess *time.Time
+	err := db.
+		Model(&entities.LoginAttempt{}).
+		Select("MAX(created_at)").
+		Where("email = ? AND success = ? AND created_at > ?", email, true, since).
+		Scan(&lastSuccess).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if lastSuccess != nil {
+		since = *lastSuccess
+	}
+
+	return countFailures(db, "email = ?", email, since)
+}
+
+// countFailures учитывает и незавершенные попытки: пока пароль проверяется, она считается неудачной
+func countFailures(db *gorm.DB, condition string, value string, since time.Time) (int64, time.Time, error) {
+	var result struct {
+		Failures int64
+		Last     *time.Time
+	}
+	err := db.
+		Model(&entities.LoginAttempt{}).
+		Select("COUNT(*) AS failures, MAX(created_at) AS last").
+		Where(condition, value).
+		Where("reason IN ? AND created_at > ?", []string{
+			entities.LoginReasonInvalidCredentials,
+			entities.LoginReasonInvalidOTP,
+			entities.LoginReasonPending,
+		}, since).
+		Scan(&result).Error
+	if err != nil || result.Last == nil {
+		return 0, time.Time{}, err
+	}
+	return result.Failures, *result.Last, nil
+}
+
+func (r *LoginAttemptRepository) Find(ctx context.Context, filter map[string]interface{}, limit int) ([]entities.LoginAttempt, error) {
+	query := r.db.WithContext(ctx).Model(&entities.LoginAttempt{})
+
+	if email, ok := filter["email"]; ok {
+		query = query.Where("email = ?", email)
+	}
+	if ip, ok := filter["ip"]; ok {
+		query = query.Where("ip = ?", ip)
+	}
+	if userID, ok := filter["user_id"]; ok {
+		query = query.Where("user_id = ?", userID)
+	}
+	if failedOnly, ok := filter["failed_only"].(bool); ok && failedOnly {
+		query = query.Where("success = ?", false)
+	}
+
+	var attempts []entities.LoginAttempt
+	err := query.Order("created_at DESC").Limit(limit).Find(&attempts).Error
+	return attempts, err
+}
+
+// Suspicious группирует неудачные входы по IP и возвращает адреса, где их не меньше minFailures
+func (r *LoginAttemptRepository) Suspicious(ctx context.Context, since time.Time, minFailures int) ([]entities.LoginActivity, error) {
+	var activity []entities.LoginActivity
+	err := r.db.WithContext(ctx).
+		Model(&entities.LoginAttempt{}).
+		Select("ip, COUNT(*) AS failures, COUNT(DISTINCT email) AS accounts, MAX(created_at) AS last_attempt").
+		Where("success = ? AND created_at > ?", false, since).
+		Group("ip").
+		Having("COUNT(*) >= ?", minFailures).
+		Order("failures DESC").
+		Scan(&activity).Error
+	return activity, err
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+
+	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type OrganizerApplicationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizerApplicationRepository(db *gorm.DB) interfaces.OrganizerApplicationRepository {
+	return &OrganizerApplicationRepository{db: db}
+}
+
+func (r *OrganizerApplicationRepository) Create(ctx context.Context, application *entities.OrganizerApplication) error {
+	return r.db.WithContext(ctx).Create(application).Error
+}
+
+func (r *OrganizerApplicationRepository) FindByID(ctx context.Context, id uint) (*entities.OrganizerApplication, error) {
+	var application entities.OrganizerApplication
+	if err := r.db.WithContext(ctx).First(&application, id).Error; err != nil {
+		return nil, err
+	}
+
+	applications := []entities.OrganizerApplication{application}
+	if err := r.attachUsers(ctx, applications); err != nil {
+		return nil, err
+	}
+	return &applications[0], nil
+}
+
+// FindLatestByUser возвращает последнюю заявку пользователя
+func (r *OrganizerApplicationRepository) FindLatestByUser(ctx context.Context, userID uint) (*entities.OrganizerApplication, error) {
+	var application entities.OrganizerApplication
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&application).Error
+	if err != nil {
+		return nil, err
+	}
+	return &application, nil
+}
+
+// FindByStatus возвращает заявки с указанным статусом, пустой статус - все заявки
+func (r *OrganizerApplicationRepository) FindByStatus(ctx context.Context, status string) ([]entities.OrganizerApplication, error) {
+	query := r.db.WithContext(ctx).Order("created_at ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var applications []entities.OrganizerApplication
+	if err := query.Find(&applications).Error; err != nil {
+		return nil, err
+	}
+	if err := r.attachUsers(ctx, applications); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+func (r *OrganizerApplicationRepository) Update(ctx context.Context, application *entities.OrganizerApplication) error {
+	return r.db.WithContext(ctx).Omit("User").Save(application).Error
+}
+
+func (r *OrganizerApplicationRepository) attachUsers(ctx context.Context, applications []entities.OrganizerApplication) error {
+	if len(applications) == 0 {
+		return nil
+	}
+
+	userIDs := make([]uint, 0, len(applications))
+	for _, application := range applications {
+		userIDs = append(userIDs, application.UserID)
+	}
+
+	var users []entities.User
+	if err := r.db.WithContext(ctx).Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return err
+	}
+
+	usersByID := make(map[uint]entities.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+	for i := range applications {
+		applications[i].User = usersByID[applications[i].UserID]
+	}
+	return nil
+}
@@ -6,16 +6,26 @@ import (
 
 // GORM модели для миграции (без бизнес-логики)
 type UserModel struct {
-	ID           uint   `gorm:"primaryKey"`
-	Username     string `gorm:"unique;not null"`
-	Email        string `gorm:"unique;not null"`
-	PasswordHash string `gorm:"not null"`
-	Role         string `gorm:"not null;default:'user'"`
-	AvatarURL    string
-	IsBlocked    bool `gorm:"default:false"`
-	LastOnline   time.Time
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID                uint   `gorm:"primaryKey"`
+	Username          string `gorm:"unique;not null"`
+	Email             string `gorm:"unique;not null"`
+	PasswordHash      string `gorm:"not null"`
+	Role              string `gorm:"not null;default:'user'"`
+	AvatarURL         string
+	AvatarKey         string `gorm:"not null;default:''"`
+	Bio               string `gorm:"type:text;not null;default:''"`
+	HideParticipation bool   `gorm:"not null;default:false"`
+	IsBlocked         bool   `gorm:"default:false"`
+	TokenVersion      int    `gorm:"not null;default:0"`
+	EmailVerified     bool   `gorm:"not null;default:false"`
+	LockedUntil       *time.Time
+	TOTPSecret        string `gorm:"column:totp_secret"`
+	TOTPEnabled       bool   `gorm:"column:totp_enabled;not null;default:false"`
+	TOTPLastStep      int64  `gorm:"column:totp_last_step;not null;default:0"`
+	LastOnline        time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	OrganizerVerified *time.Time `gorm:"column:organizer_verified_at"`
 }
 
 type EventModel struct {
@@ -30,6 +40,8 @@ type EventModel struct {
 	Address         string `gorm:"type:text"`
 	IsVerified      bool   `gorm:"default:false"`
 	IsActive        bool   `gorm:"default:true"`
+	CancelledAt     *time.Time
+	CancelReason    string `gorm:"type:text;not null;default:''"`
 	CreatorID       uint
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
@@ -48,19 +60,32 @@ type EventTagModel struct {
 }
 
 type EventMediaModel struct {
-	ID         uint `gorm:"primaryKey"`
-	EventID    uint
-	FileURL    string `gorm:"not null"`
-	FileType   string `gorm:"not null"`
-	OrderIndex int    `gorm:"default:0"`
-	CreatedAt  time.Time
+	ID           uint `gorm:"primaryKey"`
+	EventID      uint
+	FileURL      string `gorm:"not null"`
+	FileType     string `gorm:"not null"`
+	StorageKey   string
+	ThumbnailURL string
+	CardURL      string
+	Status       string `gorm:"not null;default:'ready'"`
+	OrderIndex   int    `gorm:"default:0"`
+	UploadedAt   time.Time
+	CreatedAt    time.Time
 }
 
 type EventParticipantModel struct {
-	EventID  uint   `gorm:"primaryKey"`
-	UserID   uint   `gorm:"primaryKey"`
-	Status   string `gorm:"not null;default:'going'"`
-	JoinedAt time.Time
+	EventID        uint   `gorm:"primaryKey"`
+	UserID         uint   `gorm:"primaryKey"`
+	Status         string `gorm:"not null;default:'going'"`
+	JoinedAt       time.Time
+	RemindersMuted bool `gorm:"not null;default:false"`
+}
+
+type EventReminderModel struct {
+	EventID       uint `gorm:"primaryKey"`
+	UserID        uint `gorm:"primaryKey"`
+	OffsetMinutes int  `gorm:"primaryKey"`
+	SentAt        time.Time
 }
 
 type CommentModel struct {
@@ -70,6 +95,8 @@ type CommentModel struct {
 	UserID    uint
 	ParentID  *uint
 	Score     int `gorm:"default:0"`
+	Upvotes   int `gorm:"not null;default:0"`
+	Downvotes int `gorm:"not null;default:0"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	IsDeleted bool `gorm:"default:false"`
@@ -91,6 +118,77 @@ type NotificationModel struct {
 	CreatedAt time.Time
 }
 
+type RefreshTokenModel struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	TokenHash  string `gorm:"unique;not null"`
+	FamilyID   string `gorm:"not null;index"`
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *uint
+	CreatedAt  time.Time
+}
+
+type VerificationTokenModel struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	TokenHash string `gorm:"unique;not null"`
+	Purpose   string `gorm:"not null"`
+	Email     string `gorm:"not null"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type UserIdentityModel struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"not null;uniqueIndex:idx_user_provider"`
+	Provider    string `gorm:"not null;uniqueIndex:idx_provider_subject;uniqueIndex:idx_user_provider"`
+	Subject     string `gorm:"not null;uniqueIndex:idx_provider_subject"`
+	Email       string
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+type OAuthStateModel struct {
+	ID           uint   `gorm:"primaryKey"`
+	StateHash    string `gorm:"unique;not null"`
+	Provider     string `gorm:"not null"`
+	CodeVerifier string `gorm:"not null"`
+	UserID       *uint
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+type RecoveryCodeModel struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type LoginAttemptModel struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    *uint
+	Email     string `gorm:"not null;index"`
+	IP        string `gorm:"column:ip;not null;index"`
+	Success   bool   `gorm:"not null"`
+	Reason    string `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+type OrganizerApplicationModel struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	Message    string `gorm:"not null"`
+	Status     string `gorm:"not null;default:'pending'"`
+	ReviewedBy *uint
+	ReviewNote string
+	CreatedAt  time.Time
+	ReviewedAt *time.Time
+}
+
 type AdminActionModel struct {
 	ID          uint `gorm:"primaryKey"`
 	AdminID     uint
@@ -22,3 +22,164 @@ func Migrate(db *gorm.DB) {
 
 	log.Println("Database structure check completed")
 }
+
+// schemaUpdates идемпотентные изменения схемы поверх исходной структуры БД
+var schemaUpdates = []string{
+	// Раздельные счетчики голосов для ранжирования комментариев
+	`ALTER TABLE comments ADD COLUMN IF NOT EXISTS upvotes integer NOT NULL DEFAULT 0`,
+	`ALTER TABLE comments ADD COLUMN IF NOT EXISTS downvotes integer NOT NULL DEFAULT 0`,
+	`UPDATE comments c SET
+		upvotes = (SELECT COUNT(*) FROM comment_votes v WHERE v.comment_id = c.id AND v.vote_type = 'upvote'),
+		downvotes = (SELECT COUNT(*) FROM comment_votes v WHERE v.comment_id = c.id AND v.vote_type = 'downvote')
+	WHERE c.upvotes = 0 AND c.downvotes = 0
+		AND EXISTS (SELECT 1 FROM comment_votes v WHERE v.comment_id = c.id)`,
+
+	// Ключ объекта в хранилище для удаления медиафайлов
+	`ALTER TABLE event_media ADD COLUMN IF NOT EXISTS storage_key text`,
+
+	// Размеры фотографий после фоновой обработки
+	`ALTER TABLE event_media ADD COLUMN IF NOT EXISTS thumbnail_url text`,
+	`ALTER TABLE event_media ADD COLUMN IF NOT EXISTS card_url text`,
+	`ALTER TABLE event_media ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'ready'`,
+
+	// Refresh-токены с ротацией по семействам
+	`CREATE TABLE IF NOT EXISTS refresh_tokens (
+		id bigserial PRIMARY KEY,
+		user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		token_hash text NOT NULL UNIQUE,
+		family_id text NOT NULL,
+		expires_at timestamp with time zone NOT NULL,
+		revoked_at timestamp with time zone,
+		replaced_by bigint,
+		created_at timestamp with time zone NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id)`,
+
+	// Версия токенов пользователя: увеличивается, чтобы отозвать выданные JWT
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version integer NOT NULL DEFAULT 0`,
+
+	// Заявки на статус проверенного организатора
+	`CREATE TABLE IF NOT EXISTS organizer_applications (
+		id bigserial PRIMARY KEY,
+		user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		message text NOT NULL,
+		status text NOT NULL DEFAULT 'pending',
+		reviewed_by bigint REFERENCES users(id) ON DELETE SET NULL,
+		review_note text NOT NULL DEFAULT '',
+		created_at timestamp with time zone NOT NULL DEFAULT now(),
+		reviewed_at timestamp with time zone
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_organizer_applications_user_id ON organizer_applications (user_id)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_organizer_applications_pending ON organizer_applications (user_id) WHERE status = 'pending'`,
+
+	// Подтверждение почты: существующие аккаунты считаются подтвержденными, новые - нет
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified boolean NOT NULL DEFAULT true`,
+	`ALTER TABLE users ALTER COLUMN email_verified SET DEFAULT false`,
+
+	// Одноразовые токены из писем: подтверждение почты и сброс пароля
+	`CREATE TABLE IF NOT EXISTS verification_tokens (
+		id bigserial PRIMARY KEY,
+		user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		token_hash text NOT NULL UNIQUE,
+		purpose text NOT NULL,
+		email text NOT NULL,
+		expires_at timestamp with time zone NOT NULL,
+		used_at timestamp with time zone,
+		created_at timestamp with time zone NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_verification_tokens_user_id ON verification_tokens (user_id, purpose)`,
+
+	// Защита от перебора паролей: журнал входов и временная блокировка аккаунта
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until timestamp with time zone`,
+	`CREATE TABLE IF NOT EXISTS login_attempts (
+		id bigserial PRIMARY KEY,
+		user_id bigint REFERENCES users(id) ON DELETE SET NULL,
+		email text NOT NULL,
+		ip text NOT NULL,
+		success boolean NOT NULL,
+		reason text NOT NULL,
+		created_at timestamp with time zone NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_login_attempts_email ON login_attempts (email, created_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_login_attempts_ip ON login_attempts (ip, created_at)`,
+
+	// Двухфакторная аутентификация (TOTP) и одноразовые коды восстановления
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_secret text NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_enabled boolean NOT NULL DEFAULT false`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS totp_last_step bigint NOT NULL DEFAULT 0`,
+	`CREATE TABLE IF NOT EXISTS recovery_codes (
+		id bigserial PRIMARY KEY,
+		user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		code_hash text NOT NULL,
+		used_at timestamp with time zone,
+		created_at timestamp with time zone NOT NULL DEFAULT now()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id)`,
+
+	// Вход через внешних провайдеров (OIDC/OAuth2)
+	`CREATE TABLE IF NOT EXISTS user_identities (
+		id bigserial PRIMARY KEY,
+		user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		provider text NOT NULL,
+		subject text NOT NULL,
+		email text NOT NULL DEFAULT '',
+		created_at timestamp with time zone NOT NULL DEFAULT now(),
+		last_login_at timestamp with time zone,
+		UNIQUE (provider, subject),
+		UNIQUE (user_id, provider)
+	)`,
+	`CREATE TABLE IF NOT EXISTS oauth_states (
+		id bigserial PRIMARY KEY,
+		state_hash text NOT NULL UNIQUE,
+		provider text NOT NULL,
+		code_verifier text NOT NULL,
+		user_id bigint REFERENCES users(id) ON DELETE CASCADE,
+		expires_at timestamp with time zone NOT NULL,
+		created_at timestamp with time zone NOT NULL DEFAULT now()
+	)`,
+
+	// Профиль: описание и ключ аватара в хранилище для удаления старого файла
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS bio text NOT NULL DEFAULT ''`,
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_key text NOT NULL DEFAULT ''`,
+
+	// Настройка приватности публичного профиля
+	`ALTER TABLE users ADD COLUMN IF NOT EXISTS hide_participation boolean NOT NULL DEFAULT false`,
+
+	// Отмена мероприятия с указанием причины
+	`ALTER TABLE events ADD COLUMN IF NOT EXISTS cancelled_at timestamp with time zone`,
+	`ALTER TABLE events ADD COLUMN IF NOT EXISTS cancel_reason text NOT NULL DEFAULT ''`,
+
+	// Напоминания о мероприятиях: отказ участника и журнал отправленных,
+	// первичный ключ не дает отправить одно напоминание дважды
+	`ALTER TABLE event_participants ADD COLUMN IF NOT EXISTS reminders_muted boolean NOT NULL DEFAULT false`,
+	`CREATE TABLE IF NOT EXISTS event_reminders (
+		event_id bigint NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		offset_minutes integer NOT NULL,
+		sent_at timestamp with time zone NOT NULL DEFAULT now(),
+		PRIMARY KEY (event_id, user_id, offset_minutes)
+	)`,
+
+	// Отметка о проверке организатора отдельно от роли; раньше проверенными
+	// считались все организаторы, поэтому при добавлении колонки они ее получают
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'organizer_verified_at') THEN
+			ALTER TABLE users ADD COLUMN organizer_verified_at timestamp with time zone;
+			UPDATE users SET organizer_verified_at = now() WHERE role = 'organizer';
+		END IF;
+	END $$`,
+}
+
+// ApplySchemaUpdates применяет недостающие изменения схемы
+func ApplySchemaUpdates(db *gorm.DB) error {
+	for _, statement := range schemaUpdates {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	log.Println("Database schema is up to date")
+	return nil
+}
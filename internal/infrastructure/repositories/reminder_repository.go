@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) interfaces.ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// FindDue ищет идущих участников мероприятий, которые начинаются в интервале (from, to]
+// и еще не получили напоминание с этим смещением
+func (r *ReminderRepository) FindDue(ctx context.Context, offsetMinutes int, from, to time.Time, limit int) ([]entities.DueReminder, error) {
+	var due []entities.DueReminder
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT p.event_id, p.user_id, e.title, e.event_date
+		FROM event_participants p
+		JOIN events e ON e.id = p.event_id
+		WHERE p.status = ? AND NOT p.reminders_muted
+			AND e.is_active AND e.cancelled_at IS NULL
+			AND e.event_date > ? AND e.event_date <= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM event_reminders r
+				WHERE r.event_id = p.event_id AND r.user_id = p.user_id AND r.offset_minutes = ?
+			)
+		ORDER BY e.event_date, p.event_id, p.user_id
+		LIMIT ?
+	`, entities.ParticipantStatusGoing, from, to, offsetMinutes, limit).Scan(&due).Error
+	return due, err
+}
+
+// Send записывает отметку и уведомление в одной транзакции. false означает,
+// что напоминание уже было отправлено (например, другим экземпляром сервера)
+func (r *ReminderRepository) Send(ctx context.Context, reminder *entities.EventReminder, notification *entities.Notification) (bool, error) {
+	sent := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reminder)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Create(notification).Error; err != nil {
+			return err
+		}
+		sent = true
+		return nil
+	})
+	return sent, err
+}
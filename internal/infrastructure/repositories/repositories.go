@@ -13,6 +13,13 @@ type Repositories struct {
 	Comment      interfaces.CommentRepository
 	Notification interfaces.NotificationRepository
 	Admin        interfaces.AdminRepository
+	RefreshToken interfaces.RefreshTokenRepository
+	Organizer    interfaces.OrganizerApplicationRepository
+	Verification interfaces.VerificationTokenRepository
+	LoginAttempt interfaces.LoginAttemptRepository
+	RecoveryCode interfaces.RecoveryCodeRepository
+	Identity     interfaces.IdentityRepository
+	Reminder     interfaces.ReminderRepository
 }
 
 // Factory functions для создания репозиториев
@@ -23,5 +30,12 @@ func NewRepositories(db *gorm.DB) *Repositories {
 		Comment:      NewCommentRepository(db),
 		Notification: NewNotificationRepository(db),
 		Admin:        NewAdminRepository(db),
+		RefreshToken: NewRefreshTokenRepository(db),
+		Organizer:    NewOrganizerApplicationRepository(db),
+		Verification: NewVerificationTokenRepository(db),
+		LoginAttempt: NewLoginAttemptRepository(db),
+		RecoveryCode: NewRecoveryCodeRepository(db),
+		Identity:     NewIdentityRepository(db),
+		Reminder:     NewReminderRepository(db),
 	}
 }
@@ -40,8 +40,14 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entiti
 	return &user, nil
 }
 
-func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+// UpdateColumns записывает только перечисленные колонки. Полного Save у пользователя нет:
+// копия, прочитанная до долгой операции, откатила бы блокировку, роль или 2FA, измененные за это время
+func (r *UserRepository) UpdateColumns(ctx context.Context, user *entities.User, columns ...string) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.User{}).
+		Where("id = ?", user.ID).
+		Select(columns).
+		Updates(user).Error
 }
 
 func (r *UserRepository) UpdateLastOnline(ctx context.Context, userID uint) error {
@@ -71,10 +77,80 @@ func (r *UserRepository) UnblockUser(ctx context.Context, userID uint) error {
 		Update("is_blocked", false).Error
 }
 
+func (r *UserRepository) GetStatus(ctx context.Context, userID uint) (*entities.UserStatus, error) {
+	var status entities.UserStatus
+	err := r.db.WithContext(ctx).Model(&entities.User{}).
+		Select("id AS user_id, role, is_blocked, token_version, totp_enabled").
+		Where("id = ?", userID).
+		Take(&status).Error
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// IncrementTokenVersion делает недействительными все выданные пользователю access-токены
+func (r *UserRepository) IncrementTokenVersion(ctx context.Context, userID uint) error {
+	// Поле только для чтения в сущности, чтобы Save со старой копией не откатил версию
+	return r.db.WithContext(ctx).
+		Exec("UPDATE users SET token_version = token_version + 1 WHERE id = ?", userID).Error
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, userID uint, role string) error {
+	return r.db.WithContext(ctx).Model(&entities.User{}).
+		Where("id = ?", userID).
+		Update("role", role).Error
+}
+
+func (r *UserRepository) GetByRoles(ctx context.Context, roles []string) ([]entities.User, error) {
+	var users []entities.User
+	err := r.db.WithContext(ctx).
+		Where("role IN ? AND is_blocked = ?", roles, false).
+		Find(&users).Error
+	return users, err
+}
+
 func (r *UserRepository) GetAdmins(ctx context.Context) ([]entities.User, error) {
 	var users []entities.User
 	err := r.db.WithContext(ctx).
-		Where("role = ?", "admin").
+		Where("role IN ?", []string{entities.RoleAdmin, entities.RoleSuperAdmin}).
 		Find(&users).Error
 	return users, err
 }
+
+func (r *UserRepository) SetLockedUntil(ctx context.Context, userID uint, lockedUntil *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.User{}).
+		Where("id = ?", userID).
+		Update("locked_until", lockedUntil).Error
+}
+
+// SetOrganizerVerified отмечает проверку организатора; nil снимает отметку
+func (r *UserRepository) SetOrganizerVerified(ctx context.Context, userID uint, verifiedAt *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.User{}).
+		Where("id = ?", userID).
+		Update("organizer_verified_at", verifiedAt).Error
+}
+
+// UpdateTwoFactor сохраняет секрет TOTP и признак включения второго фактора
+func (r *UserRepository) UpdateTwoFactor(ctx context.Context, userID uint, secret string, enabled bool) error {
+	return r.db.WithContext(ctx).
+		Exec("UPDATE users SET totp_secret = ?, totp_enabled = ?, totp_last_step = 0 WHERE id = ?", secret, enabled, userID).Error
+}
+
+// ConsumeTOTPStep запоминает использованный шаг TOTP; false означает повтор уже принятого кода
+func (r *UserRepository) ConsumeTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Exec("UPDATE users SET totp_last_step = ? WHERE id = ? AND totp_last_step < ?", step, userID, step)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *UserRepository) FindByUsername(ctx context.Context, username string) (*entities.User, error) {
+	var user entities.User
+	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
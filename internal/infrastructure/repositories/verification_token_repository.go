@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"auth-system/internal/application/interfaces"
+	"auth-system/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type VerificationTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewVerificationTokenRepository(db *gorm.DB) interfaces.VerificationTokenRepository {
+	return &VerificationTokenRepository{db: db}
+}
+
+func (r *VerificationTokenRepository) Create(ctx context.Context, token *entities.VerificationToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *VerificationTokenRepository) FindByHash(ctx context.Context, hash, purpose string) (*entities.VerificationToken, error) {
+	var token entities.VerificationToken
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ? AND purpose = ?", hash, purpose).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed гасит токен и возвращает false, если он уже был использован
+func (r *VerificationTokenRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entities.VerificationToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// InvalidateForUser гасит все неиспользованные токены пользователя с данным назначением
+func (r *VerificationTokenRepository) InvalidateForUser(ctx context.Context, userID uint, purpose string) error {
+	return r.db.WithContext(ctx).
+		Model(&entities.VerificationToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"auth-system/internal/application/interfaces"
+)
+
+// LocalStorage хранит файлы на диске, используется в разработке и тестах
+type LocalStorage struct {
+	baseDir string
+	baseURL string
+}
+
+func NewLocalStorage(baseDir, baseURL string) (interfaces.MediaStorage, error) {
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{
+		baseDir: baseDir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+func (s *LocalStorage) Upload(ctx context.Context, key string, content io.Reader, size int64, contentType string) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, content); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path не дает ключу выйти за пределы базовой папки
+func (s *LocalStorage) path(key string) (string, error) {
+	path := filepath.Join(s.baseDir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(s.baseDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", ErrInvalidKey
+	}
+	return path, nil
+}
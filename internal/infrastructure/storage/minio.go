@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"auth-system/internal/application/interfaces"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinioStorage хранит файлы в S3-совместимом хранилище
+type MinioStorage struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+func NewMinioStorage(ctx context.Context, endpoint, accessKey, secretKey, bucket, publicURL string, useSSL bool) (interfaces.MediaStorage, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Создаем бакет при первом запуске
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return nil, err
+		}
+	}
+
+	if publicURL == "" {
+		scheme := "http://"
+		if useSSL {
+			scheme = "https://"
+		}
+		publicURL = scheme + endpoint + "/" + bucket
+	}
+
+	return &MinioStorage{
+		client:    client,
+		bucket:    bucket,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}, nil
+}
+
+func (s *MinioStorage) Upload(ctx context.Context, key string, content io.Reader, size int64, contentType string) (string, error) {
+	_, err := s.client.PutObject(ctx, s.bucket, key, content, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.publicURL + "/" + key, nil
+}
+
+func (s *MinioStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+}
+
+func (s *MinioStorage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,5 @@
+package storage
+
+import "errors"
+
+var ErrInvalidKey = errors.New("invalid storage key")
@@ -0,0 +1,94 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// Регистрируем декодеры поддерживаемых форматов
+	_ "image/gif"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Variant размер, в который приводится загруженная фотография
+type Variant struct {
+	Name    string
+	MaxSize int
+}
+
+var (
+	Thumbnail = Variant{Name: "thumb", MaxSize: 320}
+	Card      = Variant{Name: "card", MaxSize: 800}
+	Full      = Variant{Name: "full", MaxSize: 1920}
+)
+
+// Variants все размеры, которые строятся для каждой фотографии
+var Variants = []Variant{Thumbnail, Card, Full}
+
+// Avatar размер аватара пользователя; в набор фотографий мероприятия не входит
+var Avatar = Variant{Name: "avatar", MaxSize: 512}
+
+const jpegQuality = 85
+
+// MaxPixels предел размера изображения: маленький файл может описывать огромную
+// картинку, и ее декодирование съело бы всю память (decompression bomb)
+const MaxPixels = 50_000_000
+
+// ErrTooLarge изображение больше MaxPixels
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Decode декодирует изображение и поворачивает его согласно EXIF Orientation.
+// Сами метаданные при этом отбрасываются: в результат попадают только пиксели.
+// Размеры проверяются по заголовку до того, как выделяется память под пиксели
+func Decode(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return applyOrientation(img, exifOrientation(data)), nil
+}
+
+// Resize уменьшает изображение так, чтобы большая сторона не превышала maxSize
+func Resize(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSize && height <= maxSize {
+		return img
+	}
+
+	if width >= height {
+		height = height * maxSize / width
+		width = maxSize
+	} else {
+		width = width * maxSize / height
+		height = maxSize
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, max(width, 1), max(height, 1)))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// EncodeJPEG кодирует изображение в JPEG без метаданных, прозрачность заливается белым
+func EncodeJPEG(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	canvas := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), img, bounds.Min, draw.Over)
+	return jpeg.Encode(w, canvas, &jpeg.Options{Quality: jpegQuality})
+}
@@ -7,32 +7,40 @@ import (
 )
 
 type JWTUtil interface {
-	GenerateToken(userID uint, email, role string) (string, error)
+	GenerateToken(userID uint, email, role string, tokenVersion int) (string, error)
 	ParseToken(tokenString string) (*Claims, error)
+	AccessTTL() time.Duration
 }
 
 type jwtUtil struct {
-	secret []byte
+	secret    []byte
+	accessTTL time.Duration
 }
 
-func NewJWTUtil(secret string) JWTUtil {
-	return &jwtUtil{secret: []byte(secret)}
+func NewJWTUtil(secret string, accessTTL time.Duration) JWTUtil {
+	return &jwtUtil{secret: []byte(secret), accessTTL: accessTTL}
+}
+
+func (j *jwtUtil) AccessTTL() time.Duration {
+	return j.accessTTL
 }
 
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Role   string `json:"role"`
-	Email  string `json:"email"`
+	UserID       uint   `json:"user_id"`
+	Role         string `json:"role"`
+	Email        string `json:"email"`
+	TokenVersion int    `json:"ver"`
 	jwt.RegisteredClaims
 }
 
-func (j *jwtUtil) GenerateToken(userID uint, email, role string) (string, error) {
+func (j *jwtUtil) GenerateToken(userID uint, email, role string, tokenVersion int) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:       userID,
+		Email:        email,
+		Role:         role,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}